@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/ZhanibekTau/go-sdk/pkg/config"
 	ginHelper "github.com/ZhanibekTau/go-sdk/pkg/gin"
+	"github.com/ZhanibekTau/go-sdk/pkg/metrics"
 	"github.com/ZhanibekTau/go-sdk/pkg/tracer"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/getsentry/sentry-go"
@@ -25,6 +26,7 @@ type App struct {
 	isInit      bool
 	AppExt      interface{}
 	Location    *time.Location
+	commands    map[string]Command
 }
 
 func (app *App) InitBaseConfig() (*config.BaseConfig, error) {
@@ -87,6 +89,10 @@ func (app *App) initApp() error {
 		app.Location = location
 	}
 
+	if mErr := metrics.InitMetrics(); mErr != nil {
+		fmt.Println("Metrics config error: ", mErr.Error())
+	}
+
 	tErr := app.initTraceClient()
 
 	if tErr != nil {
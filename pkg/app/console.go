@@ -0,0 +1,120 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"github.com/ZhanibekTau/go-sdk/pkg/exception"
+	"os"
+	"sort"
+)
+
+// Command - консольная команда приложения
+type Command struct {
+	Name        string
+	Description string
+	// Run - выполнение команды, args - аргументы после имени команды
+	Run func(app *App, args []string) error
+}
+
+// RegisterCommand - регистрирует консольную команду, повторная регистрация имени заменяет команду
+func (app *App) RegisterCommand(command Command) {
+	if app.commands == nil {
+		app.commands = map[string]Command{}
+	}
+
+	app.commands[command.Name] = command
+}
+
+// RunConsole Запуск консольной команды: args[0] - имя команды, остальное - ее аргументы,
+// например app.RunConsole(os.Args[1:]). Без имени или с именем help выводится список команд
+func (app *App) RunConsole(args []string) error {
+	if !app.isInit {
+		iErr := app.initApp()
+
+		if iErr != nil {
+			return iErr
+		}
+	}
+
+	// встроенные команды регистрируются до PrepareConsole, чтобы приложение могло их заменить
+	app.registerBuiltinCommands()
+
+	if iConsole, ok := app.AppExt.(IConsole); ok {
+		if pcErr := iConsole.PrepareConsole(app); pcErr != nil {
+			return pcErr
+		}
+	} else {
+		fmt.Println("App does not implement IConsole, skipping PrepareConsole.")
+	}
+
+	if len(args) == 0 || args[0] == "help" {
+		app.printCommands()
+
+		return nil
+	}
+
+	command, ok := app.commands[args[0]]
+
+	if !ok {
+		app.printCommands()
+
+		return fmt.Errorf("unknown command: %s", args[0])
+	}
+
+	return command.Run(app, args[1:])
+}
+
+// registerBuiltinCommands - команды, доступные в каждом приложении:
+//
+//	exception:catalogue [--format=json|markdown] [--output=file]   выгрузить каталог ошибок (exception.Define)
+//
+// Каталог можно обновлять при сборке: //go:generate go run . exception:catalogue --format=markdown --output=docs/errors.md
+func (app *App) registerBuiltinCommands() {
+	app.RegisterCommand(Command{
+		Name:        "exception:catalogue",
+		Description: "export error catalogue [--format=json|markdown] [--output=file]",
+		Run: func(_ *App, args []string) error {
+			flags := flag.NewFlagSet("exception:catalogue", flag.ContinueOnError)
+			format := flags.String("format", exception.CatalogueFormatJson, "json or markdown")
+			output := flags.String("output", "", "output file, stdout by default")
+
+			if err := flags.Parse(args); err != nil {
+				return err
+			}
+
+			if *output == "" {
+				return exception.WriteCatalogue(os.Stdout, *format)
+			}
+
+			file, err := os.Create(*output)
+
+			if err != nil {
+				return err
+			}
+
+			if err = exception.WriteCatalogue(file, *format); err != nil {
+				_ = file.Close()
+
+				return err
+			}
+
+			return file.Close()
+		},
+	})
+}
+
+func (app *App) printCommands() {
+	names := make([]string, 0, len(app.commands))
+
+	for name := range app.commands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	_, _ = fmt.Fprintln(os.Stdout, "Available commands:")
+
+	for _, name := range names {
+		_, _ = fmt.Fprintf(os.Stdout, "  %-24s %s\n", name, app.commands[name].Description)
+	}
+}
@@ -0,0 +1,5 @@
+package app
+
+type IConsole interface {
+	PrepareConsole(app *App) error
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ApiKey - API ключ клиента (партнера или внутреннего сервиса). Сам ключ не хранится, только его хэш
+type ApiKey struct {
+	Id        int        `json:"id" gorm:"primaryKey"`
+	ClientId  string     `json:"client_id"`
+	KeyHash   string     `json:"key_hash"`
+	Scopes    Scopes     `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// IsExpired - истек ли срок действия ключа
+func (k *ApiKey) IsExpired() bool {
+	return k.ExpiresAt != nil && k.ExpiresAt.Before(time.Now())
+}
+
+// HasScopes - проверяет, что ключу выданы все перечисленные скоупы
+func (k *ApiKey) HasScopes(scopes ...string) bool {
+	for _, scope := range scopes {
+		if !k.Scopes.Contains(scope) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Scopes - список скоупов, в БД хранится строкой через запятую
+type Scopes []string
+
+// Contains - содержит ли список скоуп
+func (s Scopes) Contains(scope string) bool {
+	for _, v := range s {
+		if v == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Value - запись в БД
+func (s Scopes) Value() (driver.Value, error) {
+	return strings.Join(s, ","), nil
+}
+
+// Scan - чтение из БД
+func (s *Scopes) Scan(value any) error {
+	var str string
+
+	switch v := value.(type) {
+	case string:
+		str = v
+	case []byte:
+		str = string(v)
+	case nil:
+		*s = Scopes{}
+
+		return nil
+	default:
+		return errors.New("unsupported type for scopes")
+	}
+
+	result := Scopes{}
+
+	for _, scope := range strings.Split(str, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			result = append(result, scope)
+		}
+	}
+
+	*s = result
+
+	return nil
+}
+
+// HashApiKey - хэш API ключа, по которому ключ ищется в хранилище
+func HashApiKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// GenerateApiKey - генерирует новый API ключ и его хэш. Ключ отдается клиенту, в хранилище пишется только хэш
+func GenerateApiKey() (key string, hash string, err error) {
+	bytes := make([]byte, 32)
+
+	if _, err = rand.Read(bytes); err != nil {
+		return "", "", err
+	}
+
+	key = hex.EncodeToString(bytes)
+
+	return key, HashApiKey(key), nil
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// IApiKeyStore - хранилище API ключей
+type IApiKeyStore interface {
+	// GetByHash - возвращает ключ по хэшу, nil если ключ не найден
+	GetByHash(ctx context.Context, hash string) (*ApiKey, error)
+}
+
+// NewGormApiKeyStore - хранилище API ключей в БД
+func NewGormApiKeyStore(client *gorm.DB, table string) *GormApiKeyStore {
+	if table == "" {
+		table = "api_keys"
+	}
+
+	return &GormApiKeyStore{
+		client: client,
+		table:  table,
+	}
+}
+
+// GormApiKeyStore - хранилище API ключей в БД
+type GormApiKeyStore struct {
+	client *gorm.DB
+	table  string
+}
+
+func (s *GormApiKeyStore) GetByHash(ctx context.Context, hash string) (*ApiKey, error) {
+	var apiKey ApiKey
+	result := s.client.WithContext(ctx).Table(s.table).Where("key_hash = ?", hash).First(&apiKey)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &apiKey, nil
+}
+
+// NewRedisApiKeyStore - хранилище API ключей в редисе, ключи лежат в JSON по ключу prefix + хэш
+func NewRedisApiKeyStore(redisClient *redis.Client, prefix string) *RedisApiKeyStore {
+	if prefix == "" {
+		prefix = "api_key:"
+	}
+
+	return &RedisApiKeyStore{
+		redisClient: redisClient,
+		prefix:      prefix,
+	}
+}
+
+// RedisApiKeyStore - хранилище API ключей в редисе
+type RedisApiKeyStore struct {
+	redisClient *redis.Client
+	prefix      string
+}
+
+func (s *RedisApiKeyStore) GetByHash(ctx context.Context, hash string) (*ApiKey, error) {
+	val, err := s.redisClient.Get(ctx, s.prefix+hash).Result()
+
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var apiKey ApiKey
+
+	if err = json.Unmarshal([]byte(val), &apiKey); err != nil {
+		return nil, err
+	}
+
+	return &apiKey, nil
+}
+
+// Save - записывает ключ в редис, ttl берется из срока действия ключа
+func (s *RedisApiKeyStore) Save(ctx context.Context, apiKey *ApiKey) error {
+	jsonKey, err := json.Marshal(apiKey)
+
+	if err != nil {
+		return err
+	}
+
+	if apiKey.ExpiresAt != nil {
+		return s.redisClient.SetArgs(ctx, s.prefix+apiKey.KeyHash, jsonKey, redis.SetArgs{ExpireAt: *apiKey.ExpiresAt}).Err()
+	}
+
+	return s.redisClient.Set(ctx, s.prefix+apiKey.KeyHash, jsonKey, 0).Err()
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// CanonicalString - строка, которая подписывается HMAC: метод, путь с query, хэш тела, время и nonce
+func CanonicalString(method string, path string, bodyHash string, timestamp string, nonce string) string {
+	return strings.Join([]string{strings.ToUpper(method), path, bodyHash, timestamp, nonce}, "\n")
+}
+
+// Sign - HMAC-SHA256 подпись строки в hex
+func Sign(secret string, canonical string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(canonical))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// BodyHash - sha256 хэш тела запроса в hex
+func BodyHash(body []byte) string {
+	hash := sha256.Sum256(body)
+
+	return hex.EncodeToString(hash[:])
+}
+
+// readBody - читает тело запроса и возвращает его обратно в запрос. limit - максимум байт, 0 - без ограничения,
+// тело больше лимита - ErrBodyTooLarge
+func readBody(req *http.Request, limit int64) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return []byte{}, nil
+	}
+
+	reader := io.Reader(req.Body)
+
+	if limit > 0 {
+		reader = io.LimitReader(req.Body, limit+1)
+	}
+
+	bodyBytes, err := io.ReadAll(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if limit > 0 && int64(len(bodyBytes)) > limit {
+		return nil, ErrBodyTooLarge
+	}
+
+	req.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+
+	return bodyBytes, nil
+}
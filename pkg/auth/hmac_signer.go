@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"github.com/ZhanibekTau/go-sdk/pkg/constants"
+	"github.com/google/uuid"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// NewHmacSigner - подписывает исходящие запросы для сервисов, защищенных HmacMiddleware
+func NewHmacSigner(clientId string, secret string) *HmacSigner {
+	return &HmacSigner{
+		clientId: clientId,
+		secret:   secret,
+	}
+}
+
+// HmacSigner - подпись исходящих запросов
+type HmacSigner struct {
+	clientId string
+	secret   string
+}
+
+// Sign - подписывает запрос, проставляет заголовки клиента, времени, nonce и подписи
+func (s *HmacSigner) Sign(req *http.Request) error {
+	body, err := readBody(req, 0)
+
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	nonce := uuid.New().String()
+	canonical := CanonicalString(req.Method, req.URL.RequestURI(), BodyHash(body), timestamp, nonce)
+
+	req.Header.Set(constants.ClientIdHeaderName, s.clientId)
+	req.Header.Set(constants.TimestampHeaderName, timestamp)
+	req.Header.Set(constants.NonceHeaderName, nonce)
+	req.Header.Set(constants.SignatureHeaderName, Sign(s.secret, canonical))
+
+	return nil
+}
+
+// Transport - http.RoundTripper, который подписывает каждый запрос. Если base nil, используется http.DefaultTransport
+func (s *HmacSigner) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &signedTransport{signer: s, base: base}
+}
+
+type signedTransport struct {
+	signer *HmacSigner
+	base   http.RoundTripper
+}
+
+func (t *signedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTripper не должен менять исходный запрос
+	req = req.Clone(req.Context())
+
+	if err := t.signer.Sign(req); err != nil {
+		return nil, err
+	}
+
+	return t.base.RoundTrip(req)
+}
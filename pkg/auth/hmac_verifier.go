@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"github.com/ZhanibekTau/go-sdk/pkg/constants"
+	"github.com/redis/go-redis/v9"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrSignatureMissing = errors.New("request signature is required")
+	ErrSignatureInvalid = errors.New("request signature is invalid")
+	ErrTimestampInvalid = errors.New("request timestamp is invalid or expired")
+	ErrUnknownClient    = errors.New("unknown client")
+	ErrReplayedRequest  = errors.New("request has already been processed")
+	ErrBodyTooLarge     = errors.New("request body is too large")
+)
+
+// ISecretStore - хранилище секретов HMAC по идентификатору клиента
+type ISecretStore interface {
+	// GetSecret - возвращает секрет клиента, пустую строку если клиент не найден
+	GetSecret(ctx context.Context, clientId string) (string, error)
+}
+
+// StaticSecretStore - секреты клиентов из конфига
+type StaticSecretStore map[string]string
+
+func (s StaticSecretStore) GetSecret(_ context.Context, clientId string) (string, error) {
+	return s[clientId], nil
+}
+
+// NewHmacVerifier - проверка подписанных запросов. Nonce хранятся в редисе, повтор запроса с тем же nonce отклоняется
+func NewHmacVerifier(secrets ISecretStore, redisClient *redis.Client) *HmacVerifier {
+	return &HmacVerifier{
+		secrets:     secrets,
+		redisClient: redisClient,
+		tolerance:   5 * time.Minute,
+		noncePrefix: "hmac_nonce:",
+		maxBodySize: 10 << 20,
+	}
+}
+
+// HmacVerifier - проверка HMAC подписи входящих запросов
+type HmacVerifier struct {
+	secrets     ISecretStore
+	redisClient *redis.Client
+	tolerance   time.Duration
+	noncePrefix string
+	maxBodySize int64
+}
+
+// SetTolerance - допустимое расхождение времени запроса с временем сервера
+func (v *HmacVerifier) SetTolerance(tolerance time.Duration) *HmacVerifier {
+	v.tolerance = tolerance
+
+	return v
+}
+
+// SetNoncePrefix - префикс ключей nonce в редисе
+func (v *HmacVerifier) SetNoncePrefix(prefix string) *HmacVerifier {
+	v.noncePrefix = prefix
+
+	return v
+}
+
+// SetMaxBodySize - максимальный размер подписанного тела в байтах, по умолчанию 10 МБ, 0 - без ограничения
+func (v *HmacVerifier) SetMaxBodySize(maxBodySize int64) *HmacVerifier {
+	v.maxBodySize = maxBodySize
+
+	return v
+}
+
+// Verify - проверяет подпись запроса и возвращает идентификатор клиента
+func (v *HmacVerifier) Verify(req *http.Request) (string, error) {
+	clientId := req.Header.Get(constants.ClientIdHeaderName)
+	timestamp := req.Header.Get(constants.TimestampHeaderName)
+	nonce := req.Header.Get(constants.NonceHeaderName)
+	signature := req.Header.Get(constants.SignatureHeaderName)
+
+	if clientId == "" || timestamp == "" || nonce == "" || signature == "" {
+		return "", ErrSignatureMissing
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+
+	if err != nil {
+		return "", ErrTimestampInvalid
+	}
+
+	if diff := time.Since(time.Unix(unix, 0)); diff > v.tolerance || diff < -v.tolerance {
+		return "", ErrTimestampInvalid
+	}
+
+	ctx := req.Context()
+	secret, err := v.secrets.GetSecret(ctx, clientId)
+
+	if err != nil {
+		return "", err
+	}
+
+	if secret == "" {
+		return "", ErrUnknownClient
+	}
+
+	body, err := readBody(req, v.maxBodySize)
+
+	if err != nil {
+		return "", err
+	}
+
+	expected := Sign(secret, CanonicalString(req.Method, req.URL.RequestURI(), BodyHash(body), timestamp, nonce))
+
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return "", ErrSignatureInvalid
+	}
+
+	// nonce живет столько же, сколько допустимо окно времени запроса, после этого запрос отклонится по времени
+	isNew, err := v.redisClient.SetNX(ctx, v.noncePrefix+clientId+":"+nonce, 1, 2*v.tolerance).Result()
+
+	if err != nil {
+		return "", err
+	}
+
+	if !isNew {
+		return "", ErrReplayedRequest
+	}
+
+	return clientId, nil
+}
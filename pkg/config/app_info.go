@@ -14,6 +14,8 @@ type AppInfo struct {
 	RequestUrl    string
 	ServiceName   string
 	AppEnv        string
+	// ClientId - идентификатор аутентифицированного клиента (партнера/сервиса) при вызове по API ключу или HMAC подписи
+	ClientId string
 }
 
 func (s *AppInfo) GenerateRequestId() {
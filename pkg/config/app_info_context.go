@@ -0,0 +1,33 @@
+package config
+
+import "context"
+
+// appInfoKey - ключ AppInfo в context.Context
+type appInfoKey struct{}
+
+// WithAppInfo - контекст с данными запроса, для сервисов и репозиториев, которые принимают context.Context
+func WithAppInfo(ctx context.Context, appInfo *AppInfo) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, appInfoKey{}, appInfo)
+}
+
+// AppInfoFromContext - данные запроса из контекста, nil если их нет.
+// Принимает и *gin.Context: AppInfo берется по ключу app_info
+func AppInfoFromContext(ctx context.Context) *AppInfo {
+	if ctx == nil {
+		return nil
+	}
+
+	if appInfo, ok := ctx.Value(appInfoKey{}).(*AppInfo); ok {
+		return appInfo
+	}
+
+	if appInfo, ok := ctx.Value("app_info").(*AppInfo); ok {
+		return appInfo
+	}
+
+	return nil
+}
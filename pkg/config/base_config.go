@@ -1,5 +1,16 @@
 package config
 
+import "slices"
+
+// DevelopmentEnvs - окружения разработки (APP_ENV), в которых допустимы отладочные данные в ответах и сидеры.
+// Пустое или неизвестное окружение считается боевым
+var DevelopmentEnvs = []string{"local", "dev", "test"}
+
+// IsDevelopmentEnv - является ли окружение окружением разработки, см. DevelopmentEnvs
+func IsDevelopmentEnv(env string) bool {
+	return slices.Contains(DevelopmentEnvs, env)
+}
+
 // BaseConfig Основной конфиг приложения
 type BaseConfig struct {
 	Name           string `mapstructure:"APP_NAME" json:"app_name"`
@@ -2,3 +2,6 @@ package constants
 
 const ContentTypeText string = "text/plain"
 const ContentTypeHtml string = "text/html"
+const ContentTypeJson string = "application/json"
+const ContentTypeXml string = "application/xml"
+const ContentTypeMsgPack string = "application/msgpack"
@@ -2,3 +2,4 @@ package constants
 
 const JSON string = "json"
 const XML string = "xml"
+const MsgPack string = "msgpack"
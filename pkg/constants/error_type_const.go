@@ -5,10 +5,14 @@ import "net/http"
 const (
 	NotFound            = "not_found"
 	AccessDenied        = "access_denied"
+	Unauthorized        = "unauthorized"
+	TooManyRequests     = "too_many_requests"
+	Conflict            = "conflict"
 	OperationFailed     = "operation_failed"
 	IncorrectParams     = "incorrect_parameters"
 	ValidationError     = "validation_error"
 	InternalServerError = "internal_server_error"
+	ServiceUnavailable  = "service_unavailable"
 )
 
 // GetErrorTypeByStatusCode возвращает тип ошибки для респонза по хттп статус коду
@@ -20,10 +24,18 @@ func GetErrorTypeByStatusCode(statusCode int) string {
 		return InternalServerError
 	case http.StatusForbidden:
 		return AccessDenied
+	case http.StatusUnauthorized:
+		return Unauthorized
+	case http.StatusTooManyRequests:
+		return TooManyRequests
+	case http.StatusConflict:
+		return Conflict
 	case http.StatusNotAcceptable:
 		return OperationFailed
 	case http.StatusNotFound:
 		return NotFound
+	case http.StatusServiceUnavailable:
+		return ServiceUnavailable
 	case http.StatusBadRequest:
 		return IncorrectParams
 	default:
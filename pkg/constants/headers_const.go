@@ -2,5 +2,20 @@ package constants
 
 const RequestIdHeaderName string = "Request-Id"
 const LanguageHeaderName string = "Accept-Language"
+const AcceptHeaderName string = "Accept"
 const CityHeaderName string = "City-Id"
 const UserHeaderName string = "User-Id"
+const ApiKeyHeaderName string = "X-Api-Key"
+const ClientIdHeaderName string = "X-Client-Id"
+const TimestampHeaderName string = "X-Timestamp"
+const NonceHeaderName string = "X-Nonce"
+const SignatureHeaderName string = "X-Signature"
+const RateLimitLimitHeaderName string = "X-RateLimit-Limit"
+const RateLimitRemainingHeaderName string = "X-RateLimit-Remaining"
+const RateLimitResetHeaderName string = "X-RateLimit-Reset"
+const RetryAfterHeaderName string = "Retry-After"
+const IdempotencyKeyHeaderName string = "Idempotency-Key"
+const IdempotentReplayedHeaderName string = "Idempotent-Replayed"
+const CacheHeaderName string = "X-Cache"
+const ETagHeaderName string = "ETag"
+const LastModifiedHeaderName string = "Last-Modified"
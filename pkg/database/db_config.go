@@ -2,10 +2,11 @@ package database
 
 // DbConfig Модель данных для описания соединения с БД
 type DbConfig struct {
-	Driver               string
-	Host                 string
-	User                 string
-	Password             string
+	Driver   string
+	Host     string
+	User     string
+	Password string
+	// Db - имя базы, для Sqlite путь к файлу, пусто или ":memory:" - база в памяти
 	Db                   string
 	Port                 string
 	SslMode              bool
@@ -17,4 +18,10 @@ type DbConfig struct {
 	ServiceName string
 	// Threshold - максимальный порог в сек, выше которого в сентри будут записыватся данные. Время в секундах,если не указано то дефолт 1 сек
 	Threshold int
+	// ReplicaHosts - реплики для чтения: host или host:port, пользователь, пароль и база как у основной БД
+	ReplicaHosts []string
+	// ReplicaPolicy - выбор реплики: ReplicaRoundRobin (по умолчанию) или ReplicaRandom
+	ReplicaPolicy string
+	// ReplicaHealthCheckInterval - интервал проверки реплик в секундах, по умолчанию 10
+	ReplicaHealthCheckInterval int
 }
@@ -3,3 +3,6 @@ package database
 const Postgres string = "postgres"
 const MsSql string = "mssql"
 const MySql string = "mysql"
+
+// Sqlite - файл или память (Db пусто или ":memory:"), для локальной разработки и тестов
+const Sqlite string = "sqlite"
@@ -1,8 +1,10 @@
 package database
 
 import (
+	"database/sql"
 	"fmt"
 	"github.com/ZhanibekTau/go-sdk/pkg/database/gorm/plugin"
+	sqliteDriver "github.com/glebarez/sqlite"
 	"github.com/go-errors/errors"
 	mysqlDriver "gorm.io/driver/mysql"
 	postgresDriver "gorm.io/driver/postgres"
@@ -14,16 +16,7 @@ import (
 
 // GetGormConnection Возвращает клиент для работы с БД
 func GetGormConnection(dbConfig DbConfig) (*gorm.DB, error) {
-	var dialector gorm.Dialector
-
-	switch dbConfig.Driver {
-	case Postgres:
-		dialector = postgresDriver.Open(getPostgresConnectionString(dbConfig))
-	case MsSql:
-		dialector = sqlserverDriver.Open(getMsSqlConnectionString(dbConfig))
-	case MySql:
-		dialector = mysqlDriver.Open(getMySqlConnectionString(dbConfig))
-	}
+	dialector := getDialector(dbConfig)
 
 	if dialector == nil {
 		return nil, errors.New("Unknown db driver: " + dbConfig.Driver)
@@ -35,6 +28,11 @@ func GetGormConnection(dbConfig DbConfig) (*gorm.DB, error) {
 		config.Logger = logger.Default.LogMode(logger.Info)
 	}
 
+	if dbConfig.Driver == Sqlite {
+		// коды ошибок Sqlite translator не разбирает, gorm переводит их в gorm.ErrDuplicatedKey и gorm.ErrForeignKeyViolated
+		config.TranslateError = true
+	}
+
 	//if dbConfig.DisableAutomaticPing {
 	config.DisableAutomaticPing = true
 	//}
@@ -51,15 +49,7 @@ func GetGormConnection(dbConfig DbConfig) (*gorm.DB, error) {
 		return nil, err
 	}
 
-	db.SetConnMaxLifetime(time.Hour)
-
-	if dbConfig.MaxOpenConnections > 0 {
-		db.SetMaxOpenConns(dbConfig.MaxOpenConnections)
-	}
-
-	if dbConfig.MaxIdleConnections > 0 {
-		db.SetMaxIdleConns(dbConfig.MaxIdleConnections)
-	}
+	setPoolLimits(db, dbConfig)
 
 	var threshold time.Duration
 
@@ -82,9 +72,76 @@ func GetGormConnection(dbConfig DbConfig) (*gorm.DB, error) {
 		fmt.Println("error init GormPluginWithTrace : ", err)
 	}
 
+	if len(dbConfig.ReplicaHosts) > 0 {
+		// пулы реплик useReplicas закрывает сам, основной пул закрывается здесь
+		if err = useReplicas(gormDb, dbConfig); err != nil {
+			_ = db.Close()
+
+			return nil, err
+		}
+	}
+
 	return gormDb, nil
 }
 
+// CloseGormConnection Закрывает клиент GetGormConnection: останавливает проверку реплик, закрывает пулы реплик и основной БД
+func CloseGormConnection(gormDb *gorm.DB) error {
+	var replicaErr error
+
+	if health, ok := gormDb.Config.Plugins[replicaPluginName].(*replicaHealth); ok {
+		replicaErr = health.close()
+	}
+
+	db, err := gormDb.DB()
+
+	if err != nil {
+		return err
+	}
+
+	if err = db.Close(); err != nil {
+		return err
+	}
+
+	return replicaErr
+}
+
+// getDialector Возвращает диалект gorm по драйверу, nil для неизвестного драйвера
+func getDialector(dbConfig DbConfig) gorm.Dialector {
+	switch dbConfig.Driver {
+	case Postgres:
+		return postgresDriver.Open(getPostgresConnectionString(dbConfig))
+	case MsSql:
+		return sqlserverDriver.Open(getMsSqlConnectionString(dbConfig))
+	case MySql:
+		return mysqlDriver.Open(getMySqlConnectionString(dbConfig))
+	case Sqlite:
+		return sqliteDriver.Open(getSqliteConnectionString(dbConfig))
+	}
+
+	return nil
+}
+
+// setPoolLimits Настраивает пул соединений
+func setPoolLimits(db *sql.DB, dbConfig DbConfig) {
+	if isSqliteMemory(dbConfig) {
+		// у каждого соединения своя база в памяти: одно соединение на все время жизни пула
+		db.SetMaxOpenConns(1)
+		db.SetConnMaxLifetime(0)
+
+		return
+	}
+
+	db.SetConnMaxLifetime(time.Hour)
+
+	if dbConfig.MaxOpenConnections > 0 {
+		db.SetMaxOpenConns(dbConfig.MaxOpenConnections)
+	}
+
+	if dbConfig.MaxIdleConnections > 0 {
+		db.SetMaxIdleConns(dbConfig.MaxIdleConnections)
+	}
+}
+
 // getPostgresConnectionString Возвращает строку (DSN) для создания соединения с Postgres
 func getPostgresConnectionString(dbConfig DbConfig) string {
 	sslMode := "disable"
@@ -107,3 +164,18 @@ func getMySqlConnectionString(dbConfig DbConfig) string {
 	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
 		dbConfig.User, dbConfig.Password, dbConfig.Host, dbConfig.Port, dbConfig.Db)
 }
+
+// getSqliteConnectionString Возвращает строку (DSN) для Sqlite, внешние ключи включены
+func getSqliteConnectionString(dbConfig DbConfig) string {
+	path := dbConfig.Db
+
+	if path == "" {
+		path = ":memory:"
+	}
+
+	return path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+}
+
+func isSqliteMemory(dbConfig DbConfig) bool {
+	return dbConfig.Driver == Sqlite && (dbConfig.Db == "" || dbConfig.Db == ":memory:")
+}
@@ -0,0 +1,259 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/ZhanibekTau/go-sdk/pkg/logger"
+	sqliteDriver "github.com/glebarez/sqlite"
+	mysqlDriver "gorm.io/driver/mysql"
+	postgresDriver "gorm.io/driver/postgres"
+	sqlserverDriver "gorm.io/driver/sqlserver"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+	"net"
+	"sync"
+	"time"
+)
+
+// Политики выбора реплики для чтения
+const (
+	ReplicaRoundRobin = "round_robin"
+	ReplicaRandom     = "random"
+)
+
+// replicaPluginName - имя плагина с состоянием реплик в gorm.Config.Plugins
+const replicaPluginName = "sdk:replicas"
+
+type primaryContextKey struct{}
+
+// ReadFromPrimary - чтение в ctx идет с основной БД, например сразу после записи (read your writes),
+// пока реплика могла еще не получить изменения. Транзакции всегда идут на основную БД
+func ReadFromPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey{}, true)
+}
+
+func isReadFromPrimary(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+
+	primary, _ := ctx.Value(primaryContextKey{}).(bool)
+
+	return primary
+}
+
+// replicaHealth - состояние реплик, недоступные реплики исключаются из выбора до следующей успешной проверки.
+// Регистрируется плагином gorm, чтобы CloseGormConnection остановил проверку и закрыл пулы реплик
+type replicaHealth struct {
+	mu        sync.RWMutex
+	replicas  map[gorm.ConnPool]string
+	healthy   map[gorm.ConnPool]bool
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+func (h *replicaHealth) Name() string {
+	return replicaPluginName
+}
+
+func (h *replicaHealth) Initialize(*gorm.DB) error {
+	return nil
+}
+
+func (h *replicaHealth) isHealthy(pool gorm.ConnPool) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	healthy, isReplica := h.healthy[pool]
+
+	return healthy || !isReplica
+}
+
+func (h *replicaHealth) filter(pools []gorm.ConnPool) []gorm.ConnPool {
+	result := make([]gorm.ConnPool, 0, len(pools))
+
+	for _, pool := range pools {
+		if h.isHealthy(pool) {
+			result = append(result, pool)
+		}
+	}
+
+	return result
+}
+
+// check - пингует реплики, изменение состояния пишется в лог
+func (h *replicaHealth) check() {
+	for pool, host := range h.replicas {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		err := pool.(*sql.DB).PingContext(ctx)
+		cancel()
+
+		h.mu.Lock()
+		wasHealthy := h.healthy[pool]
+		h.healthy[pool] = err == nil
+		h.mu.Unlock()
+
+		if err != nil && wasHealthy {
+			logger.Error("db replica %s is excluded from rotation: %s", host, err.Error())
+		} else if err == nil && !wasHealthy {
+			logger.Info("db replica %s is back in rotation", host)
+		}
+	}
+}
+
+func (h *replicaHealth) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				h.check()
+			case <-h.stop:
+				return
+			}
+		}
+	}()
+}
+
+// close - останавливает проверку и закрывает пулы реплик, повторные вызовы ничего не делают
+func (h *replicaHealth) close() error {
+	var err error
+
+	h.closeOnce.Do(func() {
+		close(h.stop)
+
+		for pool := range h.replicas {
+			err = errors.Join(err, pool.(*sql.DB).Close())
+		}
+	})
+
+	return err
+}
+
+// replicaPolicy - выбор среди доступных реплик, если доступных нет - основная БД
+type replicaPolicy struct {
+	base    dbresolver.Policy
+	primary gorm.ConnPool
+	health  *replicaHealth
+}
+
+func (p *replicaPolicy) Resolve(pools []gorm.ConnPool) gorm.ConnPool {
+	healthy := p.health.filter(pools)
+
+	switch len(healthy) {
+	case 0:
+		return p.primary
+	case 1:
+		return healthy[0]
+	}
+
+	return p.base.Resolve(healthy)
+}
+
+// useReplicas - чтение (SELECT вне транзакции) идет на реплики, запись и транзакции - на основную БД
+func useReplicas(gormDb *gorm.DB, dbConfig DbConfig) error {
+	health := &replicaHealth{replicas: map[gorm.ConnPool]string{}, healthy: map[gorm.ConnPool]bool{}, stop: make(chan struct{})}
+	dialectors := make([]gorm.Dialector, 0, len(dbConfig.ReplicaHosts))
+
+	for _, host := range dbConfig.ReplicaHosts {
+		replicaConfig := dbConfig
+		replicaConfig.Host, replicaConfig.Port = splitReplicaHost(host, dbConfig.Port)
+
+		// нужен только пул соединений, запросы выполняет основной клиент через dbresolver
+		replicaDb, err := gorm.Open(getDialector(replicaConfig), &gorm.Config{DisableAutomaticPing: true})
+
+		if err != nil {
+			return errors.Join(err, health.close())
+		}
+
+		db, err := replicaDb.DB()
+
+		if err != nil {
+			return errors.Join(err, health.close())
+		}
+
+		setPoolLimits(db, dbConfig)
+		health.replicas[db] = host
+		health.healthy[db] = true
+		dialectors = append(dialectors, connDialector(dbConfig.Driver, db))
+	}
+
+	var base dbresolver.Policy = dbresolver.StrictRoundRobinPolicy()
+
+	if dbConfig.ReplicaPolicy == ReplicaRandom {
+		base = dbresolver.RandomPolicy{}
+	}
+
+	primary := gormDb.ConnPool
+	policy := &replicaPolicy{base: base, primary: primary, health: health}
+
+	if err := gormDb.Use(dbresolver.Register(dbresolver.Config{Replicas: dialectors, Policy: policy})); err != nil {
+		return errors.Join(err, health.close())
+	}
+
+	if err := registerReplicaCallbacks(gormDb, health, primary); err != nil {
+		return errors.Join(err, health.close())
+	}
+
+	if err := gormDb.Use(health); err != nil {
+		return errors.Join(err, health.close())
+	}
+
+	interval := time.Duration(dbConfig.ReplicaHealthCheckInterval) * time.Second
+
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	health.run(interval)
+
+	return nil
+}
+
+// registerReplicaCallbacks - после выбора реплики dbresolver: чтение с ReadFromPrimary в контексте и чтение
+// с недоступной реплики переводятся на основную БД (dbresolver не вызывает политику для одной реплики)
+func registerReplicaCallbacks(gormDb *gorm.DB, health *replicaHealth, primary gorm.ConnPool) error {
+	route := func(db *gorm.DB) {
+		if _, isTx := db.Statement.ConnPool.(gorm.TxCommitter); isTx {
+			return
+		}
+
+		if isReadFromPrimary(db.Statement.Context) || !health.isHealthy(db.Statement.ConnPool) {
+			db.Statement.ConnPool = primary
+		}
+	}
+
+	callback := gormDb.Callback()
+
+	return errors.Join(
+		callback.Query().After("gorm:db_resolver").Before("gorm:query").Register("sdk:replica_routing", route),
+		callback.Row().After("gorm:db_resolver").Before("gorm:row").Register("sdk:replica_routing", route),
+		callback.Raw().After("gorm:db_resolver").Before("gorm:raw").Register("sdk:replica_routing", route),
+	)
+}
+
+// connDialector - диалект поверх уже открытого соединения реплики
+func connDialector(driver string, db *sql.DB) gorm.Dialector {
+	switch driver {
+	case MsSql:
+		return sqlserverDriver.New(sqlserverDriver.Config{Conn: db})
+	case MySql:
+		return mysqlDriver.New(mysqlDriver.Config{Conn: db})
+	case Sqlite:
+		return &sqliteDriver.Dialector{Conn: db}
+	}
+
+	return postgresDriver.New(postgresDriver.Config{Conn: db})
+}
+
+func splitReplicaHost(host string, defaultPort string) (string, string) {
+	if h, port, err := net.SplitHostPort(host); err == nil {
+		return h, port
+	}
+
+	return host, defaultPort
+}
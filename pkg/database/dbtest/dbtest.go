@@ -0,0 +1,47 @@
+package dbtest
+
+import (
+	"github.com/ZhanibekTau/go-sdk/pkg/database"
+	"gorm.io/gorm"
+	"testing"
+)
+
+// NewTestDB - отдельная база Sqlite в памяти для теста с таблицами models (AutoMigrate).
+// Плагины те же, что у GetGormConnection, база закрывается по окончании теста:
+//
+//	db := dbtest.NewTestDB(t, &User{}, &Order{})
+//	repo := repository.NewRepository[User, int](db)
+func NewTestDB(t testing.TB, models ...any) *gorm.DB {
+	t.Helper()
+
+	db, err := database.GetGormConnection(database.DbConfig{Driver: database.Sqlite, ServiceName: "test"})
+
+	if err != nil {
+		t.Fatalf("dbtest: open sqlite: %s", err)
+	}
+
+	t.Cleanup(func() {
+		_ = database.CloseGormConnection(db)
+	})
+
+	if len(models) > 0 {
+		if err = db.AutoMigrate(models...); err != nil {
+			t.Fatalf("dbtest: auto migrate: %s", err)
+		}
+	}
+
+	return db
+}
+
+// Seed - создает записи values (указатели на модели или срезы моделей) по порядку, ошибка завершает тест:
+//
+//	dbtest.Seed(t, db, &User{Name: "alice"}, &[]Order{{UserId: 1}, {UserId: 1}})
+func Seed(t testing.TB, db *gorm.DB, values ...any) {
+	t.Helper()
+
+	for _, value := range values {
+		if err := db.Create(value).Error; err != nil {
+			t.Fatalf("dbtest: seed %T: %s", value, err)
+		}
+	}
+}
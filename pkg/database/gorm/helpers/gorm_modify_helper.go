@@ -14,10 +14,13 @@ func NewGormModifyHelper[E interface{}](client *gorm.DB) *GormModifyHelper[E] {
 }
 
 // GormModifyHelper - Вспомогательный хелпер для модификации данных
+//
+// Deprecated: используйте repository.Repository, он принимает ctx запроса
 type GormModifyHelper[E interface{}] struct {
-	client  *gorm.DB
-	timeout time.Duration
-	model   E
+	client          *gorm.DB
+	timeout         time.Duration
+	model           E
+	translateErrors bool
 }
 
 func (h *GormModifyHelper[E]) SetTimeout(timeout time.Duration) *GormModifyHelper[E] {
@@ -26,13 +29,21 @@ func (h *GormModifyHelper[E]) SetTimeout(timeout time.Duration) *GormModifyHelpe
 	return h
 }
 
+// TranslateErrors - ошибки gorm и драйвера возвращаются как AppException: нарушение уникальности - 409,
+// внешнего ключа - 422, deadlock - 503 (см. translator.Translate)
+func (h *GormModifyHelper[E]) TranslateErrors() *GormModifyHelper[E] {
+	h.translateErrors = true
+
+	return h
+}
+
 func (h *GormModifyHelper[E]) Create(model *E) (*E, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), h.timeout*time.Second)
 	defer cancel()
 	result := h.client.WithContext(ctx).Create(model)
 
 	if result.Error != nil {
-		return nil, result.Error
+		return nil, translateError(h.translateErrors, result.Error)
 	}
 
 	return model, nil
@@ -44,7 +55,7 @@ func (h *GormModifyHelper[E]) BatchCreate(model *[]E) (*gorm.DB, error) {
 	result := h.client.WithContext(ctx).Create(model)
 
 	if result.Error != nil {
-		return nil, result.Error
+		return nil, translateError(h.translateErrors, result.Error)
 	}
 
 	return result, nil
@@ -56,7 +67,7 @@ func (h *GormModifyHelper[E]) Update(model *E) error {
 	result := h.client.WithContext(ctx).Save(model)
 
 	if result.Error != nil {
-		return result.Error
+		return translateError(h.translateErrors, result.Error)
 	}
 
 	return nil
@@ -68,7 +79,7 @@ func (h *GormModifyHelper[E]) Delete(model *E) error {
 	result := h.client.WithContext(ctx).Delete(model)
 
 	if result.Error != nil {
-		return result.Error
+		return translateError(h.translateErrors, result.Error)
 	}
 
 	return nil
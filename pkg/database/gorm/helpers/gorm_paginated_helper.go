@@ -2,8 +2,11 @@ package helpers
 
 import (
 	"context"
+	"errors"
 	paginator "github.com/ZhanibekTau/go-sdk/pkg/database/gorm/pagination"
+	"github.com/ZhanibekTau/go-sdk/pkg/exception"
 	"gorm.io/gorm"
+	"net/http"
 	"time"
 )
 
@@ -17,12 +20,18 @@ func NewGormPaginatedHelper[E interface{}](client *gorm.DB) *GormPaginatedHelper
 }
 
 // GormPaginatedHelper - Вспомогательный хелпер для постраничного чтения данных
+//
+// Deprecated: используйте repository.Repository, он принимает ctx запроса
 type GormPaginatedHelper[E interface{}] struct {
-	client     *gorm.DB
-	perPage    int
-	maxPerPage int
-	timeout    time.Duration
-	model      E
+	client          *gorm.DB
+	perPage         int
+	maxPerPage      int
+	timeout         time.Duration
+	model           E
+	translateErrors bool
+	countStrategy   string
+	countCache      *paginator.CountCache
+	defaultOrder    string
 }
 
 func (h *GormPaginatedHelper[E]) SetTimeout(timeout time.Duration) *GormPaginatedHelper[E] {
@@ -31,12 +40,35 @@ func (h *GormPaginatedHelper[E]) SetTimeout(timeout time.Duration) *GormPaginate
 	return h
 }
 
+// TranslateErrors - ошибки gorm и драйвера возвращаются как AppException (см. translator.Translate)
+func (h *GormPaginatedHelper[E]) TranslateErrors() *GormPaginatedHelper[E] {
+	h.translateErrors = true
+
+	return h
+}
+
 func (h *GormPaginatedHelper[E]) SetPerPage(perPage int) *GormPaginatedHelper[E] {
 	h.perPage = perPage
 
 	return h
 }
 
+// SetCountStrategy - стратегия подсчета записей (paginator.CountExact, CountSkip, CountEstimate, CountCached).
+// Для CountCached нужен cache
+func (h *GormPaginatedHelper[E]) SetCountStrategy(strategy string, cache *paginator.CountCache) *GormPaginatedHelper[E] {
+	h.countStrategy = strategy
+	h.countCache = cache
+
+	return h
+}
+
+// SetDefaultOrder - сортировка, если в запросе ее нет
+func (h *GormPaginatedHelper[E]) SetDefaultOrder(order string) *GormPaginatedHelper[E] {
+	h.defaultOrder = order
+
+	return h
+}
+
 func (h *GormPaginatedHelper[E]) Paginated(page int, callback func(client *gorm.DB) *gorm.DB) (*paginator.Paginated[E], error) {
 	var structure paginator.Paginated[E]
 	var err error
@@ -44,6 +76,9 @@ func (h *GormPaginatedHelper[E]) Paginated(page int, callback func(client *gorm.
 	paging.Page = page
 	paging.Limit = h.perPage
 	paging.MaxLimit = h.maxPerPage
+	paging.CountStrategy = h.countStrategy
+	paging.CountCache = h.countCache
+	paging.DefaultOrder = h.defaultOrder
 	ctx, cancel := context.WithTimeout(context.Background(), h.timeout*time.Second)
 	defer cancel()
 
@@ -53,7 +88,7 @@ func (h *GormPaginatedHelper[E]) Paginated(page int, callback func(client *gorm.
 	}, &structure.Items)
 
 	if err != nil {
-		return nil, err
+		return nil, translateError(h.translateErrors, err)
 	}
 
 	structure.Pagination.To = structure.Pagination.From + len(structure.Items)
@@ -66,3 +101,28 @@ func (h *GormPaginatedHelper[E]) Paginated(page int, callback func(client *gorm.
 
 	return &structure, nil
 }
+
+// CursorPaginated - страница курсорной пагинации по сортировке orders, первичный ключ добавляется автоматически.
+// Поврежденный или чужой курсор - ошибка 422
+func (h *GormPaginatedHelper[E]) CursorPaginated(cursor string, orders []paginator.CursorOrder, callback func(client *gorm.DB) *gorm.DB) (*paginator.CursorPaginated[E], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout*time.Second)
+	defer cancel()
+
+	result, err := paginator.CursorPages[E](&paginator.CursorParam{
+		DB:       callback(h.client).WithContext(ctx),
+		Cursor:   cursor,
+		Limit:    h.perPage,
+		MaxLimit: h.maxPerPage,
+		Orders:   orders,
+	})
+
+	if errors.Is(err, paginator.ErrInvalidCursor) {
+		return nil, exception.NewAppException(http.StatusUnprocessableEntity, err, map[string]any{"cursor": "invalid"})
+	}
+
+	if err != nil {
+		return nil, translateError(h.translateErrors, err)
+	}
+
+	return result, nil
+}
@@ -3,22 +3,33 @@ package helpers
 import (
 	"context"
 	"errors"
+	"github.com/ZhanibekTau/go-sdk/pkg/database/gorm/translator"
 	"gorm.io/gorm"
 	"time"
 )
 
-func NewGormReadHelper[E interface{}](client *gorm.DB) *GormPaginatedHelper[E] {
-	return &GormPaginatedHelper[E]{
+func NewGormReadHelper[E interface{}](client *gorm.DB) *GormReadHelper[E] {
+	return &GormReadHelper[E]{
 		client:  client,
 		timeout: 10,
 	}
 }
 
 // GormReadHelper - Вспомогательный хелпер для чтения данных
+//
+// Deprecated: используйте repository.Repository, он принимает ctx запроса
 type GormReadHelper[E interface{}] struct {
-	client  *gorm.DB
-	timeout time.Duration
-	model   E
+	client          *gorm.DB
+	timeout         time.Duration
+	model           E
+	translateErrors bool
+}
+
+// TranslateErrors - ошибки gorm и драйвера возвращаются как AppException (см. translator.Translate)
+func (h *GormReadHelper[E]) TranslateErrors() *GormReadHelper[E] {
+	h.translateErrors = true
+
+	return h
 }
 
 // GetByCondition Возвращает одну модель по запросу
@@ -34,7 +45,22 @@ func (h *GormReadHelper[E]) GetByCondition(callback func(client *gorm.DB) *gorm.
 	}
 
 	if result.Error != nil {
-		return nil, result.Error
+		return nil, translateError(h.translateErrors, result.Error)
+	}
+
+	return &model, nil
+}
+
+// GetByConditionOrFail Возвращает одну модель по запросу, если модели нет - gorm.ErrRecordNotFound (404 при TranslateErrors)
+func (h *GormReadHelper[E]) GetByConditionOrFail(callback func(client *gorm.DB) *gorm.DB) (*E, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout*time.Second)
+	defer cancel()
+
+	var model E
+	result := callback(h.client).WithContext(ctx).First(&model)
+
+	if result.Error != nil {
+		return nil, translateError(h.translateErrors, result.Error)
 	}
 
 	return &model, nil
@@ -53,3 +79,19 @@ func (h *GormReadHelper[E]) GetById(id int) (*E, error) {
 
 	return result, nil
 }
+
+// GetByIdOrFail Возвращает одну модель по ID, если модели нет - gorm.ErrRecordNotFound (404 при TranslateErrors)
+func (h *GormReadHelper[E]) GetByIdOrFail(id int) (*E, error) {
+	return h.GetByConditionOrFail(func(client *gorm.DB) *gorm.DB {
+		return client.Where("id = ?", id)
+	})
+}
+
+// translateError - переводит ошибку в AppException, если перевод включен
+func translateError(enabled bool, err error) error {
+	if !enabled || err == nil {
+		return err
+	}
+
+	return translator.Translate(err)
+}
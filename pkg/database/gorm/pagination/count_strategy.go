@@ -0,0 +1,151 @@
+package pagination
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ZhanibekTau/go-sdk/pkg/logger"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"strconv"
+	"time"
+)
+
+// Стратегии подсчета записей для постраничного списка
+const (
+	// CountExact - COUNT(*) параллельно с выборкой страницы, по умолчанию
+	CountExact = "exact"
+	// CountSkip - без подсчета: выбирается limit+1 запись, известно только есть ли следующая страница
+	CountSkip = "skip"
+	// CountEstimate - оценка планировщика Postgres (EXPLAIN), для больших таблиц, где точное число не нужно.
+	// На других драйверах - точный подсчет
+	CountEstimate = "estimate"
+	// CountCached - точный подсчет, закешированный в редисе на CountCache.Ttl
+	CountCached = "cached"
+)
+
+// CountCache - кеш количества записей для CountCached
+type CountCache struct {
+	Redis *redis.Client
+	// Key - ключ кеша, по умолчанию хеш SQL запроса подсчета
+	Key string
+	// Ttl - время жизни, по умолчанию минута
+	Ttl time.Duration
+}
+
+// count - количество записей запроса по стратегии. Считается по db.Model, если она задана, иначе по result
+func count(db *gorm.DB, result interface{}, strategy string, cache *CountCache) (int64, error) {
+	model := queryModel(db, result)
+
+	switch strategy {
+	case CountEstimate:
+		if db.Dialector.Name() == "postgres" {
+			return estimateCount(db, model)
+		}
+	case CountCached:
+		return cachedCount(db, model, cache)
+	}
+
+	return exactCount(db, model)
+}
+
+func exactCount(db *gorm.DB, model interface{}) (int64, error) {
+	var total int64
+	err := db.Model(model).Count(&total).Error
+
+	return total, err
+}
+
+// estimateCount - оценка количества строк из плана запроса Postgres
+func estimateCount(db *gorm.DB, model interface{}) (int64, error) {
+	stmt := db.Session(&gorm.Session{DryRun: true}).Model(model).Find(model).Statement
+
+	rows, err := db.Statement.ConnPool.QueryContext(statementContext(db), "EXPLAIN (FORMAT JSON) "+stmt.SQL.String(), stmt.Vars...)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	var plan []byte
+
+	if rows.Next() {
+		if err = rows.Scan(&plan); err != nil {
+			return 0, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var explain []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+
+	if err = json.Unmarshal(plan, &explain); err != nil || len(explain) == 0 {
+		return 0, fmt.Errorf("pagination: unexpected explain result: %s", plan)
+	}
+
+	return int64(explain[0].Plan.Rows), nil
+}
+
+// cachedCount - точный подсчет через кеш в редисе. Ошибка редиса не ломает страницу: пишется в лог,
+// и количество считается без кеша
+func cachedCount(db *gorm.DB, model interface{}, cache *CountCache) (int64, error) {
+	if cache == nil || cache.Redis == nil {
+		return 0, errors.New("pagination: CountCache.Redis is required for cached count")
+	}
+
+	key := cache.Key
+
+	if key == "" {
+		stmt := db.Session(&gorm.Session{DryRun: true}).Model(model).Count(new(int64)).Statement
+		hash := sha1.Sum([]byte(fmt.Sprint(stmt.SQL.String(), stmt.Vars)))
+		key = "pagination_count:" + hex.EncodeToString(hash[:])
+	}
+
+	ttl := cache.Ttl
+
+	if ttl == 0 {
+		ttl = time.Minute
+	}
+
+	value, err := cache.Redis.Get(statementContext(db), key).Result()
+
+	if err == nil {
+		if total, pErr := strconv.ParseInt(value, 10, 64); pErr == nil {
+			return total, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		logger.ErrorContext(statementContext(db), "pagination count cache error: "+err.Error())
+
+		return exactCount(db, model)
+	}
+
+	total, err := exactCount(db, model)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if err = cache.Redis.Set(statementContext(db), key, total, ttl).Err(); err != nil {
+		logger.ErrorContext(statementContext(db), "pagination count cache error: "+err.Error())
+	}
+
+	return total, nil
+}
+
+func statementContext(db *gorm.DB) context.Context {
+	if db.Statement.Context != nil {
+		return db.Statement.Context
+	}
+
+	return context.Background()
+}
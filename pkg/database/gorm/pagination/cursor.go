@@ -0,0 +1,102 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+)
+
+const (
+	cursorNext = "next"
+	cursorPrev = "prev"
+)
+
+// ErrInvalidCursor - курсор поврежден, подделан или выдан для другой сортировки
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// ErrCursorSecretMissing - не задан ключ подписи курсоров (SetCursorSecret или CursorParam.Secret)
+var ErrCursorSecretMissing = errors.New("pagination cursor secret is not set")
+
+var (
+	cursorSecretMu sync.RWMutex
+	cursorSecret   []byte
+)
+
+// SetCursorSecret - ключ подписи курсоров по умолчанию, должен совпадать на всех репликах сервиса
+func SetCursorSecret(secret []byte) {
+	cursorSecretMu.Lock()
+	defer cursorSecretMu.Unlock()
+
+	cursorSecret = secret
+}
+
+func getCursorSecret() []byte {
+	cursorSecretMu.RLock()
+	defer cursorSecretMu.RUnlock()
+
+	return cursorSecret
+}
+
+// cursor - содержимое курсора: значения ключей сортировки последней (первой) записи страницы
+type cursor struct {
+	Direction string            `json:"d"`
+	Order     string            `json:"o"`
+	Values    []json.RawMessage `json:"v"`
+}
+
+// encodeCursor - курсор для клиента: base64(json).base64(hmac), клиент не может его прочитать осмысленно или подменить
+func encodeCursor(c *cursor, secret []byte) (string, error) {
+	payload, err := json.Marshal(c)
+
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(signCursor(encoded, secret)), nil
+}
+
+// decodeCursor - проверяет подпись и сортировку курсора
+func decodeCursor(value string, order string, secret []byte) (*cursor, error) {
+	encoded, signature, found := strings.Cut(value, ".")
+
+	if !found {
+		return nil, ErrInvalidCursor
+	}
+
+	signatureBytes, err := base64.RawURLEncoding.DecodeString(signature)
+
+	if err != nil || !hmac.Equal(signatureBytes, signCursor(encoded, secret)) {
+		return nil, ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var result cursor
+
+	if err = json.Unmarshal(payload, &result); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if result.Order != order || (result.Direction != cursorNext && result.Direction != cursorPrev) {
+		return nil, ErrInvalidCursor
+	}
+
+	return &result, nil
+}
+
+func signCursor(encoded string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(encoded))
+
+	return mac.Sum(nil)
+}
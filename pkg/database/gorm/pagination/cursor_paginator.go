@@ -0,0 +1,307 @@
+package pagination
+
+import (
+	"encoding/json"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"reflect"
+	"strings"
+)
+
+// CursorOrder - колонка сортировки курсорной пагинации. Колонка должна быть NOT NULL
+type CursorOrder struct {
+	// Column - колонка, можно с таблицей: orders.created_at
+	Column string
+	Desc   bool
+}
+
+// CursorParam - параметры курсорной пагинации
+type CursorParam struct {
+	DB *gorm.DB
+	// Cursor - курсор из запроса клиента, пусто - первая страница
+	Cursor   string
+	Limit    int
+	MaxLimit int
+	// Orders - сортировка, первичный ключ добавляется последним для однозначного порядка.
+	// Пусто - по первичному ключу по убыванию
+	Orders []CursorOrder
+	// PrimaryKey - колонка первичного ключа, по умолчанию из модели
+	PrimaryKey string
+	// Secret - ключ подписи курсоров, по умолчанию SetCursorSecret
+	Secret []byte
+}
+
+// CursorPagination - курсоры соседних страниц
+type CursorPagination struct {
+	NextCursor string `json:"next_cursor"`
+	PrevCursor string `json:"prev_cursor"`
+	HasNext    bool   `json:"has_next"`
+	HasPrev    bool   `json:"has_prev"`
+	Limit      int    `json:"per_page"`
+}
+
+// CursorPaginated список страницы курсорной пагинации
+type CursorPaginated[E interface{}] struct {
+	Items      []*E              `json:"items"`
+	Pagination *CursorPagination `json:"pagination"`
+}
+
+// CursorPages - страница по курсору (keyset): WHERE по ключам сортировки вместо OFFSET и без COUNT,
+// поэтому скорость не зависит от номера страницы и записи не пропускаются при изменении данных
+func CursorPages[E interface{}](p *CursorParam) (*CursorPaginated[E], error) {
+	secret := p.Secret
+
+	if len(secret) == 0 {
+		secret = getCursorSecret()
+	}
+
+	if len(secret) == 0 {
+		return nil, ErrCursorSecretMissing
+	}
+
+	limit := p.Limit
+
+	if limit <= 0 {
+		limit = DefaultPaginationPerPage
+	}
+
+	if p.MaxLimit > 0 && limit > p.MaxLimit {
+		limit = p.MaxLimit
+	}
+
+	db := p.DB.Session(&gorm.Session{})
+	stmt := &gorm.Statement{DB: db}
+
+	if err := stmt.Parse(new(E)); err != nil {
+		return nil, err
+	}
+
+	orders, fields, err := cursorOrders(p, stmt.Schema)
+
+	if err != nil {
+		return nil, err
+	}
+
+	orderKey := cursorOrderKey(orders)
+	backward := false
+	var decoded *cursor
+
+	if p.Cursor != "" {
+		var dErr error
+		decoded, dErr = decodeCursor(p.Cursor, orderKey, secret)
+
+		if dErr != nil {
+			return nil, dErr
+		}
+
+		values, vErr := cursorValues(decoded, fields)
+
+		if vErr != nil {
+			return nil, vErr
+		}
+
+		backward = decoded.Direction == cursorPrev
+		db = db.Where(keysetCondition(orders, values, backward))
+	}
+
+	for _, order := range orders {
+		// при движении назад сортировка обратная, результат потом разворачивается
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: order.Column}, Desc: order.Desc != backward})
+	}
+
+	items := make([]*E, 0)
+
+	if err = db.Limit(limit + 1).Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	hasMore := len(items) > limit
+
+	if hasMore {
+		items = items[:limit]
+	}
+
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	pagination := &CursorPagination{Limit: limit}
+
+	if backward {
+		pagination.HasPrev = hasMore
+		pagination.HasNext = true
+	} else {
+		pagination.HasNext = hasMore
+		pagination.HasPrev = p.Cursor != ""
+	}
+
+	if len(items) > 0 {
+		if pagination.HasNext {
+			if pagination.NextCursor, err = itemCursor(db, items[len(items)-1], fields, cursorNext, orderKey, secret); err != nil {
+				return nil, err
+			}
+		}
+
+		if pagination.HasPrev {
+			if pagination.PrevCursor, err = itemCursor(db, items[0], fields, cursorPrev, orderKey, secret); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(items) == 0 && decoded != nil {
+		// пустая страница: вернуться можно от той же позиции в обратную сторону
+		if backward {
+			decoded.Direction = cursorNext
+			pagination.HasPrev, pagination.HasNext = false, true
+			pagination.NextCursor, err = encodeCursor(decoded, secret)
+		} else {
+			decoded.Direction = cursorPrev
+			pagination.HasPrev, pagination.HasNext = true, false
+			pagination.PrevCursor, err = encodeCursor(decoded, secret)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &CursorPaginated[E]{Items: items, Pagination: pagination}, nil
+}
+
+// cursorOrders - сортировка с первичным ключом в конце и поля модели для колонок
+func cursorOrders(p *CursorParam, modelSchema *schema.Schema) ([]CursorOrder, []*schema.Field, error) {
+	primaryKey := p.PrimaryKey
+
+	if primaryKey == "" && modelSchema.PrioritizedPrimaryField != nil {
+		primaryKey = modelSchema.PrioritizedPrimaryField.DBName
+	}
+
+	if primaryKey == "" {
+		primaryKey = "id"
+	}
+
+	orders := append(make([]CursorOrder, 0, len(p.Orders)+1), p.Orders...)
+	hasPrimaryKey := false
+
+	for _, order := range orders {
+		if columnName(order.Column) == columnName(primaryKey) {
+			hasPrimaryKey = true
+		}
+	}
+
+	if !hasPrimaryKey {
+		// направление первичного ключа как у последней колонки, чтобы можно было использовать составной индекс
+		desc := true
+
+		if len(orders) > 0 {
+			desc = orders[len(orders)-1].Desc
+		}
+
+		orders = append(orders, CursorOrder{Column: primaryKey, Desc: desc})
+	}
+
+	fields := make([]*schema.Field, len(orders))
+
+	for i, order := range orders {
+		fields[i] = modelSchema.LookUpField(columnName(order.Column))
+
+		if fields[i] == nil {
+			return nil, nil, fmt.Errorf("pagination: cursor column %s not found in model %s", order.Column, modelSchema.Name)
+		}
+	}
+
+	return orders, fields, nil
+}
+
+// keysetCondition - (a > ?) OR (a = ? AND b > ?) OR ... с учетом направления каждой колонки
+func keysetCondition(orders []CursorOrder, values []any, backward bool) clause.Expression {
+	conditions := make([]clause.Expression, 0, len(orders))
+
+	for i, order := range orders {
+		and := make([]clause.Expression, 0, i+1)
+
+		for j := 0; j < i; j++ {
+			and = append(and, clause.Eq{Column: clause.Column{Name: orders[j].Column}, Value: values[j]})
+		}
+
+		column := clause.Column{Name: order.Column}
+
+		if order.Desc != backward {
+			and = append(and, clause.Lt{Column: column, Value: values[i]})
+		} else {
+			and = append(and, clause.Gt{Column: column, Value: values[i]})
+		}
+
+		conditions = append(conditions, clause.And(and...))
+	}
+
+	return clause.Or(conditions...)
+}
+
+// cursorValues - значения курсора в типах полей модели
+func cursorValues(c *cursor, fields []*schema.Field) ([]any, error) {
+	if len(c.Values) != len(fields) {
+		return nil, ErrInvalidCursor
+	}
+
+	values := make([]any, len(fields))
+
+	for i, field := range fields {
+		value := reflect.New(field.FieldType)
+
+		if err := json.Unmarshal(c.Values[i], value.Interface()); err != nil {
+			return nil, ErrInvalidCursor
+		}
+
+		values[i] = value.Elem().Interface()
+	}
+
+	return values, nil
+}
+
+// itemCursor - курсор по ключам сортировки записи
+func itemCursor(db *gorm.DB, item any, fields []*schema.Field, direction string, orderKey string, secret []byte) (string, error) {
+	c := &cursor{Direction: direction, Order: orderKey, Values: make([]json.RawMessage, len(fields))}
+	itemValue := reflect.ValueOf(item)
+
+	for i, field := range fields {
+		value, _ := field.ValueOf(db.Statement.Context, itemValue)
+		encoded, err := json.Marshal(value)
+
+		if err != nil {
+			return "", err
+		}
+
+		c.Values[i] = encoded
+	}
+
+	return encodeCursor(c, secret)
+}
+
+// cursorOrderKey - сортировка в курсоре, курсор другой сортировки не принимается
+func cursorOrderKey(orders []CursorOrder) string {
+	parts := make([]string, len(orders))
+
+	for i, order := range orders {
+		parts[i] = order.Column
+
+		if order.Desc {
+			parts[i] += " desc"
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func columnName(column string) string {
+	if index := strings.LastIndex(column, "."); index >= 0 {
+		return column[index+1:]
+	}
+
+	return column
+}
@@ -0,0 +1,136 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ZhanibekTau/go-sdk/pkg/database/dbtest"
+	"strings"
+	"testing"
+)
+
+var testCursorSecret = []byte("test-secret")
+
+type pageItem struct {
+	ID    int `gorm:"primaryKey"`
+	Name  string
+	Score int
+}
+
+// pageItems - записи с ID 1..count для dbtest.Seed
+func pageItems(count int) *[]pageItem {
+	items := make([]pageItem, 0, count)
+
+	for i := 1; i <= count; i++ {
+		items = append(items, pageItem{ID: i, Name: "item", Score: count - i})
+	}
+
+	return &items
+}
+
+func itemIds(items []*pageItem) []int {
+	ids := make([]int, 0, len(items))
+
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+
+	return ids
+}
+
+func TestCursorPagesForward(t *testing.T) {
+	db := dbtest.NewTestDB(t, &pageItem{})
+	dbtest.Seed(t, db, pageItems(7))
+
+	var ids []int
+	cursor := ""
+
+	for page := 0; ; page++ {
+		result, err := CursorPages[pageItem](&CursorParam{DB: db, Cursor: cursor, Limit: 3, Secret: testCursorSecret})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if page > 0 && !result.Pagination.HasPrev {
+			t.Fatalf("page %d: has prev is false", page)
+		}
+
+		ids = append(ids, itemIds(result.Items)...)
+
+		if !result.Pagination.HasNext {
+			break
+		}
+
+		cursor = result.Pagination.NextCursor
+	}
+
+	if fmt.Sprint(ids) != "[7 6 5 4 3 2 1]" {
+		t.Fatalf("got %v, want [7 6 5 4 3 2 1]", ids)
+	}
+}
+
+func TestCursorPagesBackward(t *testing.T) {
+	db := dbtest.NewTestDB(t, &pageItem{})
+	dbtest.Seed(t, db, pageItems(7))
+
+	first, err := CursorPages[pageItem](&CursorParam{DB: db, Limit: 3, Secret: testCursorSecret})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := CursorPages[pageItem](&CursorParam{DB: db, Cursor: first.Pagination.NextCursor, Limit: 3, Secret: testCursorSecret})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	back, err := CursorPages[pageItem](&CursorParam{DB: db, Cursor: second.Pagination.PrevCursor, Limit: 3, Secret: testCursorSecret})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(itemIds(back.Items)) != fmt.Sprint(itemIds(first.Items)) || back.Pagination.HasPrev || !back.Pagination.HasNext {
+		t.Fatalf("got %v (has prev %v), want first page %v", itemIds(back.Items), back.Pagination.HasPrev, itemIds(first.Items))
+	}
+}
+
+func TestCursorPagesRejectsTamperedCursor(t *testing.T) {
+	db := dbtest.NewTestDB(t, &pageItem{})
+	dbtest.Seed(t, db, pageItems(5))
+
+	first, err := CursorPages[pageItem](&CursorParam{DB: db, Limit: 2, Secret: testCursorSecret})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, signature, _ := strings.Cut(first.Pagination.NextCursor, ".")
+	tampered := []string{
+		payload[:len(payload)-1] + "A." + signature,
+		payload + "." + signature[:len(signature)-1] + "A",
+		payload,
+	}
+
+	for _, cursor := range tampered {
+		_, err = CursorPages[pageItem](&CursorParam{DB: db, Cursor: cursor, Limit: 2, Secret: testCursorSecret})
+
+		if !errors.Is(err, ErrInvalidCursor) {
+			t.Fatalf("cursor %q: got %v, want ErrInvalidCursor", cursor, err)
+		}
+	}
+
+	// курсор другой сортировки
+	_, err = CursorPages[pageItem](&CursorParam{
+		DB:     db,
+		Cursor: first.Pagination.NextCursor,
+		Limit:  2,
+		Orders: []CursorOrder{{Column: "score"}},
+		Secret: testCursorSecret,
+	})
+
+	if !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("other order: got %v, want ErrInvalidCursor", err)
+	}
+}
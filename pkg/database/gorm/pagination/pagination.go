@@ -10,4 +10,6 @@ type Pagination struct {
 	Page         int   `json:"current_page"`
 	PrevPage     int   `json:"prev_page"`
 	NextPage     int   `json:"next_page"`
+	// HasNext - есть ли следующая страница, при CountSkip TotalRecords и TotalPage неизвестны (-1)
+	HasNext bool `json:"has_next"`
 }
@@ -18,3 +18,18 @@ func GetPagerRequest(ctx *gin.Context) (*PagerRequest, error) {
 
 	return &p, nil
 }
+
+func GetCursorRequest(ctx *gin.Context) (*CursorRequest, error) {
+	var p CursorRequest
+
+	if err := ctx.ShouldBindQuery(&p); err != nil {
+
+		return nil, err
+	}
+
+	if p.PerPage == 0 {
+		p.PerPage = DefaultPaginationPerPage
+	}
+
+	return &p, nil
+}
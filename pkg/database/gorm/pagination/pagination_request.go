@@ -4,3 +4,9 @@ type PagerRequest struct {
 	Page    int `form:"page"`
 	PerPage int `form:"per_page"`
 }
+
+// CursorRequest параметры курсорной пагинации из запроса
+type CursorRequest struct {
+	Cursor  string `form:"cursor"`
+	PerPage int    `form:"per_page"`
+}
@@ -3,30 +3,50 @@ package pagination
 import (
 	"errors"
 	"math"
+	"reflect"
 
 	"gorm.io/gorm"
 )
 
-// Endpoint for pagination
+// Pages постраничный список, количество записей считается по Paging.CountStrategy
 func Pages(p *Param, result interface{}) (paginator *Pagination, err error) {
 
 	var (
-		done     = make(chan bool, 1)
 		db       = p.DB.Session(&gorm.Session{})
 		defPage  = 1
 		defLimit = 20
-		count    int64
+		total    int64
+		countErr error
 		offset   int
+		done     = make(chan bool, 1)
 	)
 
-	// get all counts
-	go getCounts(db, result, done, &count)
-
 	// if not defined
 	if p.Paging == nil {
 		p.Paging = &Paging{}
 	}
 
+	skipCount := p.Paging.CountStrategy == CountSkip
+
+	// get all counts
+	if skipCount {
+		done <- true
+	} else {
+		// копии, чтобы подсчет не гонялся с выборкой страницы
+		countDB, strategy, cache := db.Session(&gorm.Session{}), p.Paging.CountStrategy, p.Paging.CountCache
+		countAll := func() {
+			total, countErr = count(countDB, result, strategy, cache)
+			done <- true
+		}
+
+		if inTransaction(db) {
+			// у транзакции одно соединение, параллельные запросы в нем невозможны
+			countAll()
+		} else {
+			go countAll()
+		}
+	}
+
 	// debug sql
 	if p.Paging.ShowSQL {
 		db = db.Debug()
@@ -36,7 +56,7 @@ func Pages(p *Param, result interface{}) (paginator *Pagination, err error) {
 		p.Paging.Limit = defLimit
 	}
 	//Обработка ограничения максимального количества записей на странице
-	if p.Paging.Limit > p.Paging.MaxLimit {
+	if p.Paging.MaxLimit > 0 && p.Paging.Limit > p.Paging.MaxLimit {
 		p.Paging.Limit = p.Paging.MaxLimit
 	}
 	// page
@@ -46,33 +66,65 @@ func Pages(p *Param, result interface{}) (paginator *Pagination, err error) {
 		offset = (p.Paging.Page - 1) * p.Paging.Limit
 	}
 	// sort
-	if len(p.Paging.OrderBy) > 0 {
-		for _, o := range p.Paging.OrderBy {
-			db = db.Order(o)
+	if len(p.Paging.OrderBy) == 0 {
+		if order := defaultOrder(db, result, p.Paging); order != "" {
+			p.Paging.OrderBy = append(p.Paging.OrderBy, order)
 		}
-	} else {
-		str := "id desc"
-		p.Paging.OrderBy = append(p.Paging.OrderBy, str)
+	}
+
+	for _, o := range p.Paging.OrderBy {
+		db = db.Order(o)
+	}
+
+	limit := p.Paging.Limit
+
+	if skipCount {
+		// лишняя запись показывает, есть ли следующая страница
+		limit++
 	}
 
 	// get
-	if errGet := db.Limit(p.Paging.Limit).Offset(offset).Find(result).Error; errGet != nil && !errors.Is(errGet, gorm.ErrRecordNotFound) {
+	errGet := db.Limit(limit).Offset(offset).Find(result).Error
+	<-done
+
+	if errGet != nil && !errors.Is(errGet, gorm.ErrRecordNotFound) {
 		return nil, errGet
 	}
-	<-done
+
+	if countErr != nil {
+		return nil, countErr
+	}
+
+	hasNext := false
+
+	if skipCount {
+		hasNext = trimExtraRow(result, p.Paging.Limit)
+		total = -1
+
+		if length := resultLength(result); !hasNext && length > 0 {
+			// последняя страница: количество известно точно. По пустой странице (за концом списка) оно неизвестно
+			total = int64(offset + length)
+		}
+	}
 
 	// total pages
-	total := int(math.Ceil(float64(count) / float64(p.Paging.Limit)))
+	totalPage := -1
+
+	if total >= 0 {
+		totalPage = int(math.Ceil(float64(total) / float64(p.Paging.Limit)))
+		hasNext = p.Paging.Page < totalPage
+	}
 
 	// construct pagination
 	paginator = &Pagination{
-		TotalRecords: count,
+		TotalRecords: total,
 		Page:         p.Paging.Page,
 		Offset:       offset,
 		Limit:        p.Paging.Limit,
-		TotalPage:    total,
+		TotalPage:    totalPage,
 		PrevPage:     p.Paging.Page,
 		NextPage:     p.Paging.Page,
+		HasNext:      hasNext,
 	}
 
 	var pge = 0
@@ -88,18 +140,75 @@ func Pages(p *Param, result interface{}) (paginator *Pagination, err error) {
 		paginator.PrevPage = p.Paging.Page - 1
 	}
 	// next page
-	if p.Paging.Page != paginator.TotalPage {
+	if hasNext {
 		paginator.NextPage = p.Paging.Page + 1
 	}
 
 	return paginator, nil
 }
 
-func getCounts(db *gorm.DB, anyType interface{}, done chan bool, count *int64) {
-	db.Select("*").Model(anyType).Count(count)
-	done <- true
+// defaultOrder - сортировка по умолчанию: Paging.DefaultOrder, IDefaultOrder модели или первичный ключ по убыванию.
+// Пусто, если у модели нет первичного ключа
+func defaultOrder(db *gorm.DB, result interface{}, paging *Paging) string {
+	if paging.DefaultOrder != "" {
+		return paging.DefaultOrder
+	}
+
+	stmt := &gorm.Statement{DB: db}
+
+	if err := stmt.Parse(queryModel(db, result)); err != nil {
+		return ""
+	}
+
+	if model, ok := reflect.New(stmt.Schema.ModelType).Interface().(IDefaultOrder); ok {
+		return model.DefaultOrder()
+	}
+
+	if stmt.Schema.PrioritizedPrimaryField != nil {
+		return stmt.Quote(stmt.Schema.Table+"."+stmt.Schema.PrioritizedPrimaryField.DBName) + " desc"
+	}
+
+	return ""
+}
+
+// queryModel - модель запроса: db.Model, если задана (выборка в DTO), иначе result
+func queryModel(db *gorm.DB, result interface{}) interface{} {
+	if db.Statement.Model != nil {
+		return db.Statement.Model
+	}
+
+	return result
+}
+
+func inTransaction(db *gorm.DB) bool {
+	committer, ok := db.Statement.ConnPool.(gorm.TxCommitter)
+
+	return ok && committer != nil
+}
+
+// trimExtraRow - убирает из результата запись сверх limit, true если она была
+func trimExtraRow(result interface{}, limit int) bool {
+	slice := reflect.Indirect(reflect.ValueOf(result))
+
+	if slice.Kind() != reflect.Slice || slice.Len() <= limit {
+		return false
+	}
+
+	slice.Set(slice.Slice(0, limit))
+
+	return true
+}
+
+func resultLength(result interface{}) int {
+	slice := reflect.Indirect(reflect.ValueOf(result))
+
+	if slice.Kind() != reflect.Slice {
+		return 0
+	}
+
+	return slice.Len()
 }
 
 func (p Pagination) IsEmpty() bool {
-	return p.TotalRecords <= 0
+	return p.TotalRecords == 0
 }
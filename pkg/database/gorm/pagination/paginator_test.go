@@ -0,0 +1,144 @@
+package pagination
+
+import (
+	"github.com/ZhanibekTau/go-sdk/pkg/database/dbtest"
+	"github.com/redis/go-redis/v9"
+	"testing"
+	"time"
+)
+
+// scoredItem - модель со своей сортировкой по умолчанию
+type scoredItem struct {
+	ID    int `gorm:"primaryKey"`
+	Score int
+}
+
+func (scoredItem) DefaultOrder() string {
+	return "score asc"
+}
+
+// pageItemView - DTO без таблицы, выборка через db.Model
+type pageItemView struct {
+	ID   int
+	Name string
+}
+
+func TestPagesExactCount(t *testing.T) {
+	db := dbtest.NewTestDB(t, &pageItem{})
+	dbtest.Seed(t, db, pageItems(25))
+
+	var items []pageItem
+	paginator, err := Pages(&Param{DB: db, Paging: &Paging{Page: 3, Limit: 10}}, &items)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if paginator.TotalRecords != 25 || paginator.TotalPage != 3 || paginator.HasNext || len(items) != 5 {
+		t.Fatalf("got total %d, pages %d, has next %v, items %d", paginator.TotalRecords, paginator.TotalPage, paginator.HasNext, len(items))
+	}
+
+	if paginator.PrevPage != 2 || paginator.NextPage != 3 || paginator.Offset != 20 {
+		t.Fatalf("got prev %d, next %d, offset %d", paginator.PrevPage, paginator.NextPage, paginator.Offset)
+	}
+}
+
+func TestPagesSkipCount(t *testing.T) {
+	db := dbtest.NewTestDB(t, &pageItem{})
+	dbtest.Seed(t, db, pageItems(25))
+
+	cases := []struct {
+		page    int
+		items   int
+		total   int64
+		hasNext bool
+	}{
+		{page: 1, items: 10, total: -1, hasNext: true},
+		{page: 3, items: 5, total: 25, hasNext: false},
+		// за концом списка количество неизвестно
+		{page: 4, items: 0, total: -1, hasNext: false},
+	}
+
+	for _, tc := range cases {
+		var items []pageItem
+		paginator, err := Pages(&Param{DB: db, Paging: &Paging{Page: tc.page, Limit: 10, CountStrategy: CountSkip}}, &items)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(items) != tc.items || paginator.TotalRecords != tc.total || paginator.HasNext != tc.hasNext {
+			t.Fatalf("page %d: got items %d, total %d, has next %v", tc.page, len(items), paginator.TotalRecords, paginator.HasNext)
+		}
+	}
+}
+
+func TestPagesCachedCountWithoutRedis(t *testing.T) {
+	db := dbtest.NewTestDB(t, &pageItem{})
+	dbtest.Seed(t, db, pageItems(7))
+
+	// недоступный редис: количество считается без кеша
+	redisClient := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 100 * time.Millisecond, MaxRetries: -1})
+	t.Cleanup(func() { _ = redisClient.Close() })
+
+	var items []pageItem
+	paginator, err := Pages(&Param{
+		DB:     db,
+		Paging: &Paging{Limit: 5, CountStrategy: CountCached, CountCache: &CountCache{Redis: redisClient}},
+	}, &items)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if paginator.TotalRecords != 7 || len(items) != 5 {
+		t.Fatalf("got total %d, items %d", paginator.TotalRecords, len(items))
+	}
+}
+
+func TestPagesDefaultOrderByPrimaryKey(t *testing.T) {
+	db := dbtest.NewTestDB(t, &pageItem{})
+	dbtest.Seed(t, db, pageItems(5))
+
+	var items []pageItem
+
+	if _, err := Pages(&Param{DB: db, Paging: &Paging{Limit: 2}}, &items); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 2 || items[0].ID != 5 || items[1].ID != 4 {
+		t.Fatalf("got %+v, want ID 5, 4", items)
+	}
+}
+
+func TestPagesDefaultOrderFromModel(t *testing.T) {
+	db := dbtest.NewTestDB(t, &scoredItem{})
+
+	dbtest.Seed(t, db, &[]scoredItem{{ID: 1, Score: 30}, {ID: 2, Score: 10}, {ID: 3, Score: 20}})
+
+	var items []scoredItem
+
+	if _, err := Pages(&Param{DB: db, Paging: &Paging{Limit: 3}}, &items); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 3 || items[0].ID != 2 || items[1].ID != 3 || items[2].ID != 1 {
+		t.Fatalf("got %+v, want ID 2, 3, 1", items)
+	}
+}
+
+func TestPagesCountsByModel(t *testing.T) {
+	db := dbtest.NewTestDB(t, &pageItem{})
+	dbtest.Seed(t, db, pageItems(12))
+
+	var views []pageItemView
+	paginator, err := Pages(&Param{DB: db.Model(&pageItem{}).Where("id > ?", 2), Paging: &Paging{Limit: 5}}, &views)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if paginator.TotalRecords != 10 || len(views) != 5 || views[0].ID != 12 {
+		t.Fatalf("got total %d, views %+v", paginator.TotalRecords, views)
+	}
+}
@@ -1,9 +1,21 @@
 package pagination
 
 type Paging struct {
-	Page     int      `json:"page"`
+	Page int `json:"page"`
+	// OrderBy - сортировка подставляется в SQL как есть, сортировку из запроса клиента задавать через query.Spec
 	OrderBy  []string `json:"order_by"`
 	Limit    int      `json:"limit"`
 	MaxLimit int
 	ShowSQL  bool
+	// CountStrategy - стратегия подсчета записей (CountExact, CountSkip, CountEstimate, CountCached), по умолчанию CountExact
+	CountStrategy string
+	// CountCache - кеш для CountCached
+	CountCache *CountCache
+	// DefaultOrder - сортировка, если OrderBy пуст. По умолчанию IDefaultOrder модели или первичный ключ по убыванию
+	DefaultOrder string
+}
+
+// IDefaultOrder - сортировка модели по умолчанию для постраничного списка, например "created_at desc"
+type IDefaultOrder interface {
+	DefaultOrder() string
 }
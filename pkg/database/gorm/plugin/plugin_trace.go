@@ -1,6 +1,7 @@
 package plugin
 
 import (
+	"github.com/ZhanibekTau/go-sdk/pkg/config"
 	"github.com/ZhanibekTau/go-sdk/pkg/tracer"
 	span2 "github.com/ZhanibekTau/go-sdk/pkg/tracer/span"
 	trace2 "go.opencensus.io/trace"
@@ -49,6 +50,11 @@ func (p *PluginTrace) Initialize(db *gorm.DB) error {
 func (p *PluginTrace) before(tracer *tracer.Tracer) func(*gorm.DB) {
 	return func(db *gorm.DB) {
 		ctx, span := tracer.CreateSpan(db.Statement.Context, "[DB]")
+
+		if appInfo := config.AppInfoFromContext(db.Statement.Context); appInfo != nil {
+			span.SetAttributes(attribute.String(span2.AttributeRequestId, appInfo.RequestId))
+		}
+
 		db.InstanceSet("otel:span", span)
 		db.Statement.Context = ctx
 	}
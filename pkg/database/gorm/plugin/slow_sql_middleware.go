@@ -2,6 +2,7 @@ package plugin
 
 import (
 	"fmt"
+	"github.com/ZhanibekTau/go-sdk/pkg/config"
 	"github.com/getsentry/sentry-go"
 	"gorm.io/gorm"
 	"time"
@@ -72,6 +73,13 @@ func (p *SlowSqlSentry) after(db *gorm.DB) {
 				mapData["Sql Error"] = db.Error.Error()
 			}
 
+			// данные запроса, если контекст передан через WithContext (см. config.WithAppInfo)
+			if appInfo := config.AppInfoFromContext(db.Statement.Context); appInfo != nil {
+				scope.SetTag("request_id", appInfo.RequestId)
+				mapData["Request Id"] = appInfo.RequestId
+				mapData["Request Url"] = appInfo.RequestMethod + " " + appInfo.RequestUrl
+			}
+
 			scope.SetContext("Sql data", mapData)
 			scope.SetLevel(sentry.LevelError)
 
@@ -0,0 +1,96 @@
+package query
+
+import (
+	paginator "github.com/ZhanibekTau/go-sdk/pkg/database/gorm/pagination"
+	"github.com/ZhanibekTau/go-sdk/pkg/exception"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+)
+
+// Query - разобранные и проверенные сортировка и фильтры запроса
+type Query struct {
+	Sorts      []Sort
+	Conditions []Condition
+}
+
+// ParseRequest - разбирает sort и filter из query параметров запроса gin
+func (s *Spec) ParseRequest(c *gin.Context) (*Query, *exception.AppException) {
+	return s.Parse(c.Request.URL.Query())
+}
+
+// Scope - фильтры и сортировка как gorm scope, подходит как scope для Repository.Paginate:
+//
+//	repo.Paginate(ctx, page, q.Scope)
+func (q *Query) Scope(db *gorm.DB) *gorm.DB {
+	return q.OrderScope(q.FilterScope(db))
+}
+
+// FilterScope - только фильтры, например для курсорной пагинации, где сортировку задает CursorOrders
+func (q *Query) FilterScope(db *gorm.DB) *gorm.DB {
+	for _, condition := range q.Conditions {
+		db = db.Where(expression(condition))
+	}
+
+	return db
+}
+
+// OrderScope - только сортировка
+func (q *Query) OrderScope(db *gorm.DB) *gorm.DB {
+	for _, sort := range q.Sorts {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: sort.Column}, Desc: sort.Desc})
+	}
+
+	return db
+}
+
+// CursorOrders - сортировка для GormPaginatedHelper.CursorPaginated
+func (q *Query) CursorOrders() []paginator.CursorOrder {
+	orders := make([]paginator.CursorOrder, len(q.Sorts))
+
+	for i, sort := range q.Sorts {
+		orders[i] = paginator.CursorOrder{Column: sort.Column, Desc: sort.Desc}
+	}
+
+	return orders
+}
+
+// expression - условие фильтра, колонка экранируется драйвером, значение передается параметром
+func expression(condition Condition) clause.Expression {
+	column := clause.Column{Name: condition.Column}
+
+	switch condition.Operator {
+	case Ne:
+		return clause.Neq{Column: column, Value: condition.Value}
+	case Gt:
+		return clause.Gt{Column: column, Value: condition.Value}
+	case Gte:
+		return clause.Gte{Column: column, Value: condition.Value}
+	case Lt:
+		return clause.Lt{Column: column, Value: condition.Value}
+	case Lte:
+		return clause.Lte{Column: column, Value: condition.Value}
+	case In:
+		return clause.IN{Column: column, Values: condition.Value.([]any)}
+	case Like:
+		return clause.Expr{SQL: "? LIKE ? ESCAPE '" + likeEscape + "'", Vars: []any{column, "%" + escapeLike(condition.Value.(string)) + "%"}}
+	case Null:
+		if condition.Value.(bool) {
+			return clause.Eq{Column: column, Value: nil}
+		}
+
+		return clause.Neq{Column: column, Value: nil}
+	}
+
+	return clause.Eq{Column: column, Value: condition.Value}
+}
+
+// likeEscape - символ экранирования в ESCAPE. Не обратный слеш: в MySQL он экранирует кавычку строкового литерала,
+// и ESCAPE '\' там не разбирается, а '\\' в PostgreSQL и SQL Server - два символа
+const likeEscape = "!"
+
+// escapeLike - %, _ и [ (шаблон SQL Server) из запроса ищутся как обычные символы
+func escapeLike(value string) string {
+	return strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_", "[", likeEscape+"[").Replace(value)
+}
@@ -0,0 +1,263 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ZhanibekTau/go-sdk/pkg/exception"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Операторы фильтра: filter[price][gte]=100, без оператора - eq
+const (
+	Eq   = "eq"
+	Ne   = "ne"
+	Gt   = "gt"
+	Gte  = "gte"
+	Lt   = "lt"
+	Lte  = "lte"
+	In   = "in"
+	Like = "like"
+	Null = "null"
+)
+
+// Типы значений фильтра, значение из запроса приводится к типу, иначе - ошибка 422
+const (
+	TypeString = "string"
+	TypeInt    = "int"
+	TypeFloat  = "float"
+	TypeBool   = "bool"
+	TypeTime   = "time"
+)
+
+var filterKeyRegexp = regexp.MustCompile(`^filter\[([^\[\]]+)\](?:\[([^\[\]]+)\])?$`)
+
+// Filter - разрешенный фильтр
+type Filter struct {
+	// Column - колонка в БД, по умолчанию имя фильтра
+	Column string
+	// Operators - разрешенные операторы, по умолчанию только eq
+	Operators []string
+	// Type - тип значения, по умолчанию TypeString
+	Type string
+}
+
+// Spec - белый список сортировок и фильтров эндпоинта. Колонки берутся только из Spec, значения запроса
+// передаются параметрами, поэтому в SQL не попадает ничего от клиента
+type Spec struct {
+	// Sorts - имя поля в sort → колонка в БД
+	Sorts map[string]string
+	// Filters - имя поля в filter[...] → фильтр
+	Filters map[string]Filter
+	// DefaultSort - сортировка, если sort не передан, в формате параметра: -created_at,name
+	DefaultSort string
+}
+
+// Sort - сортировка по колонке
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+// Condition - условие фильтра
+type Condition struct {
+	Column   string
+	Operator string
+	Value    any
+}
+
+// Parse - разбирает sort=-created_at,name и filter[status]=1&filter[price][gte]=100.
+// Неразрешенные поля, операторы и неверные значения - ошибка 422 с деталями по каждому параметру
+func (s *Spec) Parse(values url.Values) (*Query, *exception.AppException) {
+	details := make(map[string]any)
+	result := &Query{}
+
+	sortValue := values.Get("sort")
+
+	if sortValue == "" {
+		sortValue = s.DefaultSort
+	}
+
+	if sortValue != "" {
+		seen := make(map[string]bool)
+
+		for _, part := range strings.Split(sortValue, ",") {
+			part = strings.TrimSpace(part)
+			name := strings.TrimPrefix(part, "-")
+			column, allowed := s.Sorts[name]
+
+			if !allowed {
+				details["sort"] = fmt.Sprintf("sorting by %q is not allowed", name)
+
+				continue
+			}
+
+			if seen[name] {
+				continue
+			}
+
+			seen[name] = true
+			result.Sorts = append(result.Sorts, Sort{Column: column, Desc: strings.HasPrefix(part, "-")})
+		}
+	}
+
+	// ключи по порядку, чтобы одинаковые запросы давали одинаковый SQL
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		match := filterKeyRegexp.FindStringSubmatch(key)
+
+		if match == nil {
+			if strings.HasPrefix(key, "filter") {
+				details[key] = "invalid filter format, expected filter[field] or filter[field][operator]"
+			}
+
+			continue
+		}
+
+		condition, err := s.condition(match[1], match[2], values.Get(key))
+
+		if err != nil {
+			details[key] = err.Error()
+
+			continue
+		}
+
+		result.Conditions = append(result.Conditions, *condition)
+	}
+
+	if len(details) > 0 {
+		return nil, exception.NewValidationAppException(details)
+	}
+
+	return result, nil
+}
+
+// condition - условие фильтра с проверкой белого списка и типа значения
+func (s *Spec) condition(name string, operator string, value string) (*Condition, error) {
+	filter, allowed := s.Filters[name]
+
+	if !allowed {
+		return nil, fmt.Errorf("filtering by %q is not allowed", name)
+	}
+
+	if operator == "" {
+		operator = Eq
+	}
+
+	operators := filter.Operators
+
+	if len(operators) == 0 {
+		operators = []string{Eq}
+	}
+
+	if !contains(operators, operator) {
+		return nil, fmt.Errorf("operator %q is not allowed, allowed: %s", operator, strings.Join(operators, ", "))
+	}
+
+	column := filter.Column
+
+	if column == "" {
+		column = name
+	}
+
+	condition := &Condition{Column: column, Operator: operator}
+
+	switch operator {
+	case Null:
+		isNull, err := strconv.ParseBool(value)
+
+		if err != nil {
+			return nil, errors.New("expected true or false")
+		}
+
+		condition.Value = isNull
+	case In:
+		parts := strings.Split(value, ",")
+		converted := make([]any, 0, len(parts))
+
+		for _, part := range parts {
+			typed, err := convert(strings.TrimSpace(part), filter.Type)
+
+			if err != nil {
+				return nil, err
+			}
+
+			converted = append(converted, typed)
+		}
+
+		condition.Value = converted
+	case Like:
+		condition.Value = value
+	default:
+		typed, err := convert(value, filter.Type)
+
+		if err != nil {
+			return nil, err
+		}
+
+		condition.Value = typed
+	}
+
+	return condition, nil
+}
+
+// convert - приводит значение к типу фильтра
+func convert(value string, valueType string) (any, error) {
+	switch valueType {
+	case TypeInt:
+		result, err := strconv.ParseInt(value, 10, 64)
+
+		if err != nil {
+			return nil, errors.New("expected integer")
+		}
+
+		return result, nil
+	case TypeFloat:
+		result, err := strconv.ParseFloat(value, 64)
+
+		if err != nil {
+			return nil, errors.New("expected number")
+		}
+
+		return result, nil
+	case TypeBool:
+		result, err := strconv.ParseBool(value)
+
+		if err != nil {
+			return nil, errors.New("expected true or false")
+		}
+
+		return result, nil
+	case TypeTime:
+		for _, layout := range []string{time.RFC3339, time.DateTime, time.DateOnly} {
+			if result, err := time.Parse(layout, value); err == nil {
+				return result, nil
+			}
+		}
+
+		return nil, errors.New("expected date (2006-01-02) or date time (RFC 3339)")
+	}
+
+	return value, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
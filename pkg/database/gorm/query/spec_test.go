@@ -0,0 +1,113 @@
+package query
+
+import (
+	"fmt"
+	"github.com/ZhanibekTau/go-sdk/pkg/database/dbtest"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type product struct {
+	ID       int `gorm:"primaryKey"`
+	Name     string
+	Price    float64
+	Status   int
+	Archived bool
+}
+
+var productSpec = &Spec{
+	Sorts: map[string]string{"price": "price", "name": "name"},
+	Filters: map[string]Filter{
+		"status": {Type: TypeInt, Operators: []string{Eq, In}},
+		"price":  {Type: TypeFloat, Operators: []string{Gte, Lt}},
+		"name":   {Operators: []string{Like}},
+		"hidden": {Column: "archived", Type: TypeBool, Operators: []string{Ne}},
+	},
+	DefaultSort: "-price",
+}
+
+func TestSpecParseRejectsNotAllowed(t *testing.T) {
+	values := url.Values{
+		"sort":                {"secret"},
+		"filter[password]":    {"1"},
+		"filter[price][eq]":   {"1"},
+		"filter[status]":      {"new"},
+		"filter[price][x][y]": {"1"},
+	}
+
+	_, appException := productSpec.Parse(values)
+
+	if appException == nil || appException.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("got %v, want validation error", appException)
+	}
+
+	for _, key := range []string{"sort", "filter[password]", "filter[price][eq]", "filter[status]", "filter[price][x][y]"} {
+		if _, ok := appException.Context[key]; !ok {
+			t.Fatalf("no details for %s: %v", key, appException.Context)
+		}
+	}
+}
+
+func TestSpecParseSortAndConditions(t *testing.T) {
+	query, appException := productSpec.Parse(url.Values{
+		"sort":               {"name,-price,name"},
+		"filter[status]":     {"2"},
+		"filter[price][gte]": {"10.5"},
+	})
+
+	if appException != nil {
+		t.Fatal(appException)
+	}
+
+	if fmt.Sprint(query.Sorts) != "[{name false} {price true}]" {
+		t.Fatalf("got sorts %v", query.Sorts)
+	}
+
+	// условия в порядке ключей запроса, значения приведены к типу фильтра
+	if fmt.Sprintf("%#v", query.Conditions) != `[]query.Condition{query.Condition{Column:"price", Operator:"gte", Value:10.5}, query.Condition{Column:"status", Operator:"eq", Value:2}}` {
+		t.Fatalf("got conditions %#v", query.Conditions)
+	}
+}
+
+func TestQueryScope(t *testing.T) {
+	db := dbtest.NewTestDB(t, &product{})
+	dbtest.Seed(t, db, &[]product{
+		{ID: 1, Name: "apple", Price: 5, Status: 1},
+		{ID: 2, Name: "pineapple", Price: 15, Status: 2},
+		{ID: 3, Name: "banana", Price: 25, Status: 2, Archived: true},
+		{ID: 4, Name: "cherry", Price: 35, Status: 3},
+	})
+
+	cases := []struct {
+		values url.Values
+		want   string
+	}{
+		{values: url.Values{}, want: "[4 3 2 1]"},
+		{values: url.Values{"filter[status][in]": {"2,3"}, "sort": {"price"}}, want: "[2 3 4]"},
+		{values: url.Values{"filter[price][gte]": {"10"}, "filter[price][lt]": {"30"}}, want: "[3 2]"},
+		{values: url.Values{"filter[name][like]": {"apple"}, "sort": {"name"}}, want: "[1 2]"},
+		// _ и % ищутся как символы, а не как шаблон
+		{values: url.Values{"filter[name][like]": {"a_p"}}, want: "[]"},
+		{values: url.Values{"filter[name][like]": {"%"}}, want: "[]"},
+		{values: url.Values{"filter[hidden][ne]": {"true"}}, want: "[4 2 1]"},
+	}
+
+	for _, tc := range cases {
+		query, appException := productSpec.Parse(tc.values)
+
+		if appException != nil {
+			t.Fatal(appException)
+		}
+
+		var ids []int
+
+		if err := db.Model(&product{}).Scopes(query.Scope).Pluck("id", &ids).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		if fmt.Sprint(ids) != tc.want {
+			t.Fatalf("%v: got %v, want %s", tc.values, ids, tc.want)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	paginator "github.com/ZhanibekTau/go-sdk/pkg/database/gorm/pagination"
+	"github.com/ZhanibekTau/go-sdk/pkg/database/gorm/transaction"
+	"github.com/ZhanibekTau/go-sdk/pkg/database/gorm/translator"
+	"github.com/ZhanibekTau/go-sdk/pkg/exception"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/http"
+	"time"
+)
+
+// Scope - условие, сортировка и т.п. для запроса, например query.Query.Scope
+type Scope func(db *gorm.DB) *gorm.DB
+
+// NewRepository - репозиторий модели E с первичным ключом типа ID
+func NewRepository[E interface{}, ID comparable](client *gorm.DB) *Repository[E, ID] {
+	return &Repository[E, ID]{
+		client:     client,
+		timeout:    10 * time.Second,
+		perPage:    30,
+		maxPerPage: 1000,
+	}
+}
+
+// Repository - чтение и модификация модели E. Каждый метод принимает ctx запроса: отмена запроса и спаны
+// трассировки доходят до БД, timeout ограничивает запрос сверху, если у ctx нет более раннего дедлайна.
+// Внутри TxManager.WithinTx запросы идут в транзакции из ctx
+type Repository[E interface{}, ID comparable] struct {
+	client          *gorm.DB
+	timeout         time.Duration
+	perPage         int
+	maxPerPage      int
+	countStrategy   string
+	countCache      *paginator.CountCache
+	translateErrors bool
+	// bound - репозиторий привязан к транзакции через WithTx
+	bound bool
+}
+
+// SetTimeout - максимальное время запроса, 0 - без ограничения
+func (r *Repository[E, ID]) SetTimeout(timeout time.Duration) *Repository[E, ID] {
+	r.timeout = timeout
+
+	return r
+}
+
+// SetPerPage - количество записей на странице Paginate
+func (r *Repository[E, ID]) SetPerPage(perPage int) *Repository[E, ID] {
+	r.perPage = perPage
+
+	return r
+}
+
+// SetCountStrategy - стратегия подсчета записей Paginate (paginator.CountExact, CountSkip, CountEstimate, CountCached)
+func (r *Repository[E, ID]) SetCountStrategy(strategy string, cache *paginator.CountCache) *Repository[E, ID] {
+	r.countStrategy = strategy
+	r.countCache = cache
+
+	return r
+}
+
+// TranslateErrors - ошибки gorm и драйвера возвращаются как AppException (см. translator.Translate)
+func (r *Repository[E, ID]) TranslateErrors() *Repository[E, ID] {
+	r.translateErrors = true
+
+	return r
+}
+
+// WithTx - копия репозитория, работающая в транзакции tx. Транзакцию из TxManager.WithinTx
+// репозиторий берет из ctx сам, WithTx для транзакций, открытых вручную
+func (r *Repository[E, ID]) WithTx(tx *gorm.DB) *Repository[E, ID] {
+	clone := *r
+	clone.client = tx
+	clone.bound = true
+
+	return &clone
+}
+
+// Find - все записи по условиям scopes
+func (r *Repository[E, ID]) Find(ctx context.Context, scopes ...Scope) ([]*E, error) {
+	ctx, cancel := r.context(ctx)
+	defer cancel()
+
+	items := make([]*E, 0)
+
+	if err := r.db(ctx, scopes).Find(&items).Error; err != nil {
+		return nil, r.error(err)
+	}
+
+	return items, nil
+}
+
+// First - первая запись по условиям scopes, nil если записи нет
+func (r *Repository[E, ID]) First(ctx context.Context, scopes ...Scope) (*E, error) {
+	model, err := r.FirstOrFail(ctx, scopes...)
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	return model, err
+}
+
+// FirstOrFail - первая запись по условиям scopes, если записи нет - gorm.ErrRecordNotFound (404 при TranslateErrors)
+func (r *Repository[E, ID]) FirstOrFail(ctx context.Context, scopes ...Scope) (*E, error) {
+	ctx, cancel := r.context(ctx)
+	defer cancel()
+
+	var model E
+
+	if err := r.db(ctx, scopes).First(&model).Error; err != nil {
+		return nil, r.error(err)
+	}
+
+	return &model, nil
+}
+
+// GetById - запись по первичному ключу, nil если записи нет
+func (r *Repository[E, ID]) GetById(ctx context.Context, id ID, scopes ...Scope) (*E, error) {
+	return r.First(ctx, append(scopes, byId(id))...)
+}
+
+// GetByIdOrFail - запись по первичному ключу, если записи нет - gorm.ErrRecordNotFound (404 при TranslateErrors)
+func (r *Repository[E, ID]) GetByIdOrFail(ctx context.Context, id ID, scopes ...Scope) (*E, error) {
+	return r.FirstOrFail(ctx, append(scopes, byId(id))...)
+}
+
+// Exists - есть ли записи по условиям scopes
+func (r *Repository[E, ID]) Exists(ctx context.Context, scopes ...Scope) (bool, error) {
+	ctx, cancel := r.context(ctx)
+	defer cancel()
+
+	var found []int
+	result := r.db(ctx, scopes).Model(new(E)).Select("1").Limit(1).Find(&found)
+
+	if result.Error != nil {
+		return false, r.error(result.Error)
+	}
+
+	return len(found) > 0, nil
+}
+
+// Count - количество записей по условиям scopes
+func (r *Repository[E, ID]) Count(ctx context.Context, scopes ...Scope) (int64, error) {
+	ctx, cancel := r.context(ctx)
+	defer cancel()
+
+	var total int64
+
+	if err := r.db(ctx, scopes).Model(new(E)).Count(&total).Error; err != nil {
+		return 0, r.error(err)
+	}
+
+	return total, nil
+}
+
+// Create - создает запись, первичный ключ и значения по умолчанию записываются в model
+func (r *Repository[E, ID]) Create(ctx context.Context, model *E) error {
+	ctx, cancel := r.context(ctx)
+	defer cancel()
+
+	return r.error(r.conn(ctx).Create(model).Error)
+}
+
+// BatchCreate - создает записи пачками по batchSize, 0 - одним запросом
+func (r *Repository[E, ID]) BatchCreate(ctx context.Context, models []*E, batchSize int) error {
+	if len(models) == 0 {
+		return nil
+	}
+
+	ctx, cancel := r.context(ctx)
+	defer cancel()
+
+	if batchSize <= 0 {
+		batchSize = len(models)
+	}
+
+	return r.error(r.conn(ctx).CreateInBatches(models, batchSize).Error)
+}
+
+// Update - сохраняет все поля записи (Save)
+func (r *Repository[E, ID]) Update(ctx context.Context, model *E) error {
+	ctx, cancel := r.context(ctx)
+	defer cancel()
+
+	return r.error(r.conn(ctx).Save(model).Error)
+}
+
+// Updates - обновляет поля values (map или структура, нулевые поля структуры пропускаются) у записей по условиям scopes.
+// Без условий gorm возвращает gorm.ErrMissingWhereClause. Возвращает количество обновленных записей
+func (r *Repository[E, ID]) Updates(ctx context.Context, values any, scopes ...Scope) (int64, error) {
+	ctx, cancel := r.context(ctx)
+	defer cancel()
+
+	result := r.db(ctx, scopes).Model(new(E)).Updates(values)
+
+	if result.Error != nil {
+		return 0, r.error(result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// Delete - удаляет запись (soft delete, если у модели есть gorm.DeletedAt)
+func (r *Repository[E, ID]) Delete(ctx context.Context, model *E) error {
+	ctx, cancel := r.context(ctx)
+	defer cancel()
+
+	return r.error(r.conn(ctx).Delete(model).Error)
+}
+
+// Paginate - страница page по условиям scopes
+func (r *Repository[E, ID]) Paginate(ctx context.Context, page int, scopes ...Scope) (*paginator.Paginated[E], error) {
+	ctx, cancel := r.context(ctx)
+	defer cancel()
+
+	var structure paginator.Paginated[E]
+	var err error
+
+	structure.Pagination, err = paginator.Pages(&paginator.Param{
+		DB: r.db(ctx, scopes),
+		Paging: &paginator.Paging{
+			Page:          page,
+			Limit:         r.perPage,
+			MaxLimit:      r.maxPerPage,
+			CountStrategy: r.countStrategy,
+			CountCache:    r.countCache,
+		},
+	}, &structure.Items)
+
+	if err != nil {
+		return nil, r.error(err)
+	}
+
+	structure.Pagination.To = structure.Pagination.From + len(structure.Items)
+
+	if len(structure.Items) == 0 {
+		structure.Pagination.From = 0
+	}
+
+	structure.Pagination.From += 1
+
+	return &structure, nil
+}
+
+// CursorPaginate - страница курсорной пагинации по сортировке orders, первичный ключ добавляется автоматически.
+// Поврежденный или чужой курсор - ошибка 422
+func (r *Repository[E, ID]) CursorPaginate(ctx context.Context, cursor string, orders []paginator.CursorOrder, scopes ...Scope) (*paginator.CursorPaginated[E], error) {
+	ctx, cancel := r.context(ctx)
+	defer cancel()
+
+	result, err := paginator.CursorPages[E](&paginator.CursorParam{
+		DB:       r.db(ctx, scopes),
+		Cursor:   cursor,
+		Limit:    r.perPage,
+		MaxLimit: r.maxPerPage,
+		Orders:   orders,
+	})
+
+	if errors.Is(err, paginator.ErrInvalidCursor) {
+		return nil, exception.NewAppException(http.StatusUnprocessableEntity, err, map[string]any{"cursor": "invalid"})
+	}
+
+	if err != nil {
+		return nil, r.error(err)
+	}
+
+	return result, nil
+}
+
+// context - ctx запроса с ограничением timeout, более ранний дедлайн ctx сохраняется
+func (r *Repository[E, ID]) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if r.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, r.timeout)
+}
+
+// conn - транзакция из ctx (см. transaction.TxManager) или клиент репозитория
+func (r *Repository[E, ID]) conn(ctx context.Context) *gorm.DB {
+	if r.bound {
+		return r.client.WithContext(ctx)
+	}
+
+	return transaction.DB(ctx, r.client)
+}
+
+func (r *Repository[E, ID]) db(ctx context.Context, scopes []Scope) *gorm.DB {
+	db := r.conn(ctx)
+
+	for _, scope := range scopes {
+		db = scope(db)
+	}
+
+	return db
+}
+
+// error - переводит ошибку в AppException, если перевод включен
+func (r *Repository[E, ID]) error(err error) error {
+	if !r.translateErrors || err == nil {
+		return err
+	}
+
+	return translator.Translate(err)
+}
+
+func byId[ID comparable](id ID) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(clause.Eq{Column: clause.PrimaryColumn, Value: id})
+	}
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/ZhanibekTau/go-sdk/pkg/database/dbtest"
+	paginator "github.com/ZhanibekTau/go-sdk/pkg/database/gorm/pagination"
+	"github.com/ZhanibekTau/go-sdk/pkg/database/gorm/transaction"
+	"github.com/ZhanibekTau/go-sdk/pkg/exception"
+	"gorm.io/gorm"
+	"net/http"
+	"testing"
+)
+
+type user struct {
+	ID        int    `gorm:"primaryKey"`
+	Email     string `gorm:"uniqueIndex"`
+	Active    bool
+	DeletedAt gorm.DeletedAt
+}
+
+func active(db *gorm.DB) *gorm.DB {
+	return db.Where("active = ?", true)
+}
+
+// users - записи для dbtest.Seed, активны нечетные
+func users(count int) []*user {
+	result := make([]*user, 0, count)
+
+	for i := 1; i <= count; i++ {
+		result = append(result, &user{Email: string(rune('a'+i-1)) + "@test", Active: i%2 == 1})
+	}
+
+	return result
+}
+
+func TestRepositoryCrud(t *testing.T) {
+	repo := NewRepository[user, int](dbtest.NewTestDB(t, &user{})).TranslateErrors()
+	ctx := context.Background()
+	model := &user{Email: "a@test", Active: true}
+
+	if err := repo.Create(ctx, model); err != nil || model.ID == 0 {
+		t.Fatalf("create: id %d, err %v", model.ID, err)
+	}
+
+	found, err := repo.GetById(ctx, model.ID)
+
+	if err != nil || found == nil || found.Email != "a@test" {
+		t.Fatalf("get by id: %+v, %v", found, err)
+	}
+
+	updated, err := repo.Updates(ctx, map[string]any{"active": false}, func(db *gorm.DB) *gorm.DB {
+		return db.Where("id = ?", model.ID)
+	})
+
+	if err != nil || updated != 1 {
+		t.Fatalf("updates: %d, %v", updated, err)
+	}
+
+	if err = repo.Delete(ctx, model); err != nil {
+		t.Fatal(err)
+	}
+
+	// soft delete: запись не находится, но остается в таблице
+	if found, err = repo.GetById(ctx, model.ID); err != nil || found != nil {
+		t.Fatalf("get deleted: %+v, %v", found, err)
+	}
+
+	var total int64
+
+	if err = repo.client.Unscoped().Model(&user{}).Count(&total).Error; err != nil || total != 1 {
+		t.Fatalf("unscoped count: %d, %v", total, err)
+	}
+}
+
+func TestRepositoryTranslatesErrors(t *testing.T) {
+	repo := NewRepository[user, int](dbtest.NewTestDB(t, &user{})).TranslateErrors()
+	ctx := context.Background()
+
+	_, err := repo.GetByIdOrFail(ctx, 100)
+	var appException *exception.AppException
+
+	if !errors.As(err, &appException) || appException.Code != http.StatusNotFound {
+		t.Fatalf("not found: got %v", err)
+	}
+
+	if err = repo.Create(ctx, &user{Email: "a@test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.Create(ctx, &user{Email: "a@test"})
+
+	if !errors.As(err, &appException) || appException.Code != http.StatusConflict {
+		t.Fatalf("duplicate: got %v", err)
+	}
+}
+
+func TestRepositoryScopes(t *testing.T) {
+	repo := NewRepository[user, int](dbtest.NewTestDB(t, &user{}))
+	ctx := context.Background()
+
+	if err := repo.BatchCreate(ctx, users(5), 2); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := repo.Find(ctx, active)
+
+	if err != nil || len(found) != 3 {
+		t.Fatalf("find: %d, %v", len(found), err)
+	}
+
+	count, err := repo.Count(ctx, active)
+
+	if err != nil || count != 3 {
+		t.Fatalf("count: %d, %v", count, err)
+	}
+
+	exists, err := repo.Exists(ctx, func(db *gorm.DB) *gorm.DB {
+		return db.Where("email = ?", "e@test")
+	})
+
+	if err != nil || !exists {
+		t.Fatalf("exists: %v, %v", exists, err)
+	}
+}
+
+func TestRepositoryPaginate(t *testing.T) {
+	db := dbtest.NewTestDB(t, &user{})
+	dbtest.Seed(t, db, users(5))
+	repo := NewRepository[user, int](db).SetPerPage(2)
+
+	page, err := repo.Paginate(context.Background(), 3)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(page.Items) != 1 || page.Pagination.TotalRecords != 5 || page.Pagination.From != 5 || page.Pagination.To != 5 {
+		t.Fatalf("got items %d, pagination %+v", len(page.Items), page.Pagination)
+	}
+}
+
+func TestRepositoryCursorPaginateInvalidCursor(t *testing.T) {
+	paginator.SetCursorSecret([]byte("test-secret"))
+	t.Cleanup(func() { paginator.SetCursorSecret(nil) })
+
+	repo := NewRepository[user, int](dbtest.NewTestDB(t, &user{})).TranslateErrors()
+
+	_, err := repo.CursorPaginate(context.Background(), "broken", nil)
+	var appException *exception.AppException
+
+	if !errors.As(err, &appException) || appException.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("got %v, want 422", err)
+	}
+}
+
+func TestRepositoryUsesTransactionFromContext(t *testing.T) {
+	repo := NewRepository[user, int](dbtest.NewTestDB(t, &user{})).TranslateErrors()
+	txManager := transaction.NewTxManager(repo.client)
+	ctx := context.Background()
+	rollback := errors.New("rollback")
+
+	err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := repo.Create(ctx, &user{Email: "a@test"}); err != nil {
+			return err
+		}
+
+		// запись видна внутри транзакции
+		if exists, err := repo.Exists(ctx); err != nil || !exists {
+			t.Errorf("exists in tx: %v, %v", exists, err)
+		}
+
+		return rollback
+	})
+
+	if !errors.Is(err, rollback) {
+		t.Fatalf("got %v, want rollback", err)
+	}
+
+	if count, err := repo.Count(ctx); err != nil || count != 0 {
+		t.Fatalf("count after rollback: %d, %v", count, err)
+	}
+}
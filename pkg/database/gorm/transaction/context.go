@@ -0,0 +1,91 @@
+package transaction
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"sync"
+)
+
+// txContextKey - ключ транзакции в контексте: пул соединений, на котором она открыта. Пустой ключ - последняя
+// открытая транзакция, для AfterCommit
+type txContextKey struct {
+	pool gorm.ConnPool
+}
+
+// txState - транзакция в контексте: пул, на котором она открыта, соединение, глубина вложенности и хуки после коммита
+type txState struct {
+	pool  gorm.ConnPool
+	tx    *gorm.DB
+	depth int
+	mu    *sync.Mutex
+	hooks *[]func()
+}
+
+func withState(ctx context.Context, state *txState) context.Context {
+	ctx = context.WithValue(ctx, txContextKey{}, state)
+
+	return context.WithValue(ctx, txContextKey{pool: state.pool}, state)
+}
+
+// stateFromContext - последняя открытая транзакция
+func stateFromContext(ctx context.Context) (*txState, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	state, ok := ctx.Value(txContextKey{}).(*txState)
+
+	return state, ok
+}
+
+// stateForDB - транзакция, открытая на том же пуле соединений, что и db (db, его сессии и WithContext)
+func stateForDB(ctx context.Context, db *gorm.DB) (*txState, bool) {
+	if ctx == nil || db == nil || db.Config == nil || db.Config.ConnPool == nil {
+		return nil, false
+	}
+
+	state, ok := ctx.Value(txContextKey{pool: db.Config.ConnPool}).(*txState)
+
+	return state, ok
+}
+
+// FromContext - транзакция, открытая WithinTx на той же БД, что и db, с контекстом ctx. Транзакция другой БД
+// в ctx не подходит
+func FromContext(ctx context.Context, db *gorm.DB) (*gorm.DB, bool) {
+	state, ok := stateForDB(ctx, db)
+
+	if !ok {
+		return nil, false
+	}
+
+	return state.tx.WithContext(ctx), true
+}
+
+// DB - транзакция из ctx, открытая на той же БД, или db вне транзакции, в обоих случаях с контекстом ctx
+func DB(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := FromContext(ctx, db); ok {
+		return tx
+	}
+
+	return db.WithContext(ctx)
+}
+
+// AfterCommit - fn выполнится после успешного коммита внешней транзакции, например публикация события:
+//
+//	transaction.AfterCommit(ctx, func() { _ = pubSub.Publish(topic, msg) })
+//
+// При откате (в том числе до savepoint вложенного вызова) fn не выполнится. Вне транзакции fn выполняется сразу
+func AfterCommit(ctx context.Context, fn func()) {
+	state, ok := stateFromContext(ctx)
+
+	if !ok {
+		fn()
+
+		return
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	*state.hooks = append(*state.hooks, fn)
+}
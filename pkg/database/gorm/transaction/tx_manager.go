@@ -0,0 +1,142 @@
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/ZhanibekTau/go-sdk/pkg/database/gorm/translator"
+	"gorm.io/gorm"
+	"sync"
+	"time"
+)
+
+// NewTxManager - менеджер транзакций поверх db
+func NewTxManager(db *gorm.DB) *TxManager {
+	return &TxManager{
+		db:         db,
+		maxRetries: 3,
+		retryDelay: 50 * time.Millisecond,
+	}
+}
+
+// TxManager - транзакции, привязанные к context.Context: репозитории и transaction.DB берут транзакцию из ctx,
+// поэтому несколько репозиториев пишут в одной транзакции без передачи *gorm.DB
+type TxManager struct {
+	db         *gorm.DB
+	maxRetries int
+	retryDelay time.Duration
+}
+
+// SetMaxRetries - количество повторов транзакции при конфликте сериализации или deadlock, 0 - без повторов
+func (m *TxManager) SetMaxRetries(maxRetries int) *TxManager {
+	m.maxRetries = maxRetries
+
+	return m
+}
+
+// SetRetryDelay - пауза перед первым повтором, дальше удваивается
+func (m *TxManager) SetRetryDelay(delay time.Duration) *TxManager {
+	m.retryDelay = delay
+
+	return m
+}
+
+// WithinTx - выполняет fn в транзакции с уровнем изоляции по умолчанию (см. WithinTxOptions)
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return m.WithinTxOptions(ctx, nil, fn)
+}
+
+// WithinTxIsolation - выполняет fn в транзакции с уровнем изоляции level, например sql.LevelSerializable
+func (m *TxManager) WithinTxIsolation(ctx context.Context, level sql.IsolationLevel, fn func(ctx context.Context) error) error {
+	return m.WithinTxOptions(ctx, &sql.TxOptions{Isolation: level}, fn)
+}
+
+// WithinTxOptions - выполняет fn в транзакции, транзакция передается через ctx. Ошибка или паника fn - откат.
+// Вызов внутри другой транзакции той же БД создает savepoint: ошибка откатывает только изменения вложенного fn,
+// opts и повторы при этом не применяются. Внутри транзакции другой БД открывается отдельная транзакция. Внешняя транзакция при конфликте сериализации или deadlock
+// повторяется целиком, поэтому fn не должна иметь побочных эффектов вне БД - для них AfterCommit
+func (m *TxManager) WithinTxOptions(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if parent, ok := stateForDB(ctx, m.db); ok {
+		return m.nested(ctx, parent, fn)
+	}
+
+	delay := m.retryDelay
+
+	for attempt := 0; ; attempt++ {
+		err := m.run(ctx, opts, fn)
+
+		if err == nil || attempt >= m.maxRetries || !translator.IsRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+}
+
+// run - одна попытка внешней транзакции, хуки выполняются после коммита
+func (m *TxManager) run(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error {
+	hooks := make([]func(), 0)
+	var txOpts []*sql.TxOptions
+
+	if opts != nil {
+		txOpts = append(txOpts, opts)
+	}
+
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(withState(ctx, &txState{pool: m.db.Config.ConnPool, tx: tx, mu: &sync.Mutex{}, hooks: &hooks}))
+	}, txOpts...)
+
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range hooks {
+		hook()
+	}
+
+	return nil
+}
+
+// nested - вложенная транзакция через savepoint, хуки передаются родителю только при успехе
+func (m *TxManager) nested(ctx context.Context, parent *txState, fn func(ctx context.Context) error) (err error) {
+	name := fmt.Sprintf("sp_%d", parent.depth+1)
+	tx := parent.tx.WithContext(ctx)
+
+	if err = tx.SavePoint(name).Error; err != nil {
+		return err
+	}
+
+	hooks := make([]func(), 0)
+	state := &txState{pool: parent.pool, tx: parent.tx, depth: parent.depth + 1, mu: &sync.Mutex{}, hooks: &hooks}
+	panicked := true
+
+	defer func() {
+		if panicked || err != nil {
+			tx.RollbackTo(name)
+		}
+	}()
+
+	err = fn(withState(ctx, state))
+	panicked = false
+
+	if err != nil {
+		return err
+	}
+
+	parent.mu.Lock()
+	defer parent.mu.Unlock()
+
+	*parent.hooks = append(*parent.hooks, hooks...)
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZhanibekTau/go-sdk/pkg/database/dbtest"
+	"gorm.io/gorm"
+	"testing"
+)
+
+type account struct {
+	ID   int `gorm:"primaryKey"`
+	Name string
+}
+
+var errRollback = errors.New("rollback")
+
+func accountNames(t *testing.T, db *gorm.DB) string {
+	t.Helper()
+
+	var names []string
+
+	if err := db.Model(&account{}).Order("id").Pluck("name", &names).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return fmt.Sprint(names)
+}
+
+func createAccount(ctx context.Context, db *gorm.DB, name string) error {
+	return DB(ctx, db).Create(&account{Name: name}).Error
+}
+
+func TestWithinTxCommit(t *testing.T) {
+	db := dbtest.NewTestDB(t, &account{})
+	committed := false
+
+	err := NewTxManager(db).WithinTx(context.Background(), func(ctx context.Context) error {
+		AfterCommit(ctx, func() { committed = true })
+
+		if committed {
+			t.Error("after commit hook ran before commit")
+		}
+
+		return createAccount(ctx, db, "a")
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !committed || accountNames(t, db) != "[a]" {
+		t.Fatalf("committed %v, accounts %s", committed, accountNames(t, db))
+	}
+}
+
+func TestWithinTxRollback(t *testing.T) {
+	db := dbtest.NewTestDB(t, &account{})
+	committed := false
+
+	err := NewTxManager(db).WithinTx(context.Background(), func(ctx context.Context) error {
+		AfterCommit(ctx, func() { committed = true })
+
+		if err := createAccount(ctx, db, "a"); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("got %v, want rollback", err)
+	}
+
+	if committed || accountNames(t, db) != "[]" {
+		t.Fatalf("committed %v, accounts %s", committed, accountNames(t, db))
+	}
+}
+
+func TestWithinTxPanicRollsBack(t *testing.T) {
+	db := dbtest.NewTestDB(t, &account{})
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("panic is not propagated")
+			}
+		}()
+
+		_ = NewTxManager(db).WithinTx(context.Background(), func(ctx context.Context) error {
+			if err := createAccount(ctx, db, "a"); err != nil {
+				return err
+			}
+
+			panic("fail")
+		})
+	}()
+
+	if accountNames(t, db) != "[]" {
+		t.Fatalf("accounts %s, want none", accountNames(t, db))
+	}
+}
+
+func TestNestedTxSavepointRollback(t *testing.T) {
+	db := dbtest.NewTestDB(t, &account{})
+	manager := NewTxManager(db)
+	hooks := make([]string, 0)
+
+	err := manager.WithinTx(context.Background(), func(ctx context.Context) error {
+		AfterCommit(ctx, func() { hooks = append(hooks, "outer") })
+
+		if err := createAccount(ctx, db, "outer"); err != nil {
+			return err
+		}
+
+		nestedErr := manager.WithinTx(ctx, func(ctx context.Context) error {
+			AfterCommit(ctx, func() { hooks = append(hooks, "rolled back") })
+
+			if err := createAccount(ctx, db, "rolled back"); err != nil {
+				return err
+			}
+
+			return errRollback
+		})
+
+		if !errors.Is(nestedErr, errRollback) {
+			t.Errorf("nested: got %v, want rollback", nestedErr)
+		}
+
+		return manager.WithinTx(ctx, func(ctx context.Context) error {
+			AfterCommit(ctx, func() { hooks = append(hooks, "nested") })
+
+			return createAccount(ctx, db, "nested")
+		})
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// откат до savepoint отменяет только вложенный вызов вместе с его хуками
+	if accountNames(t, db) != "[outer nested]" || fmt.Sprint(hooks) != "[outer nested]" {
+		t.Fatalf("accounts %s, hooks %v", accountNames(t, db), hooks)
+	}
+}
+
+func TestTxBoundToItsDB(t *testing.T) {
+	db := dbtest.NewTestDB(t, &account{})
+	other := dbtest.NewTestDB(t, &account{})
+
+	err := NewTxManager(db).WithinTx(context.Background(), func(ctx context.Context) error {
+		// транзакция db не используется для другой БД
+		if err := createAccount(ctx, other, "other"); err != nil {
+			return err
+		}
+
+		// сессия той же БД пишет в транзакции
+		if err := createAccount(ctx, db.Session(&gorm.Session{}), "a"); err != nil {
+			return err
+		}
+
+		return errRollback
+	})
+
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("got %v, want rollback", err)
+	}
+
+	if accountNames(t, db) != "[]" || accountNames(t, other) != "[other]" {
+		t.Fatalf("accounts %s, other %s", accountNames(t, db), accountNames(t, other))
+	}
+}
+
+func TestAfterCommitOutsideTx(t *testing.T) {
+	called := false
+	AfterCommit(context.Background(), func() { called = true })
+
+	if !called {
+		t.Fatal("hook outside transaction is not called immediately")
+	}
+}
@@ -0,0 +1,243 @@
+package translator
+
+import (
+	"errors"
+	"github.com/ZhanibekTau/go-sdk/pkg/exception"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	mssql "github.com/microsoft/go-mssqldb"
+	"gorm.io/gorm"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Типы ошибок БД в ответе (поле error)
+const (
+	RecordNotFound       = "record_not_found"
+	UniqueViolation      = "unique_violation"
+	ForeignKeyViolation  = "foreign_key_violation"
+	SerializationFailure = "serialization_failure"
+)
+
+// Коды ошибок Postgres (SQLSTATE)
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// Номера ошибок MySQL
+const (
+	mysqlDuplicateEntry  = 1062
+	mysqlRowIsReferenced = 1451
+	mysqlNoReferencedRow = 1452
+	mysqlLockWaitTimeout = 1205
+	mysqlDeadlock        = 1213
+)
+
+// Номера ошибок MSSQL
+const (
+	mssqlUniqueConstraint = 2627
+	mssqlUniqueIndex      = 2601
+	mssqlConstraint       = 547
+	mssqlDeadlock         = 1205
+	mssqlSnapshotConflict = 3960
+)
+
+var (
+	mysqlKeyRegexp        = regexp.MustCompile("for key '([^']+)'")
+	mysqlConstraintRegexp = regexp.MustCompile("CONSTRAINT `([^`]+)`")
+	mssqlConstraintRegexp = regexp.MustCompile(`(?:constraint|index) ['"]([^'"]+)['"]`)
+)
+
+// dbError - ошибка для клиента без текста запроса и деталей драйвера, исходная ошибка доступна через errors.Is/As
+type dbError struct {
+	message string
+	cause   error
+}
+
+func (e *dbError) Error() string {
+	return e.message
+}
+
+func (e *dbError) Unwrap() error {
+	return e.cause
+}
+
+// driverError - ошибка драйвера, приведенная к общему виду
+type driverError struct {
+	errorType  string
+	constraint string
+	table      string
+}
+
+// Translate - переводит ошибку gorm или драйвера (Postgres, MySQL, MSSQL) в AppException:
+// запись не найдена - 404, нарушение уникальности - 409 с именем ограничения, нарушение внешнего ключа - 422,
+// конфликт сериализации и deadlock - 503 с retryable в контексте. Остальные ошибки - 500.
+// Уже готовый AppException возвращается как есть, nil для nil
+func Translate(err error) *exception.AppException {
+	if err == nil {
+		return nil
+	}
+
+	if appException, ok := exception.As(err); ok {
+		return appException
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		appException := exception.NewAppException(http.StatusNotFound, &dbError{"record not found", err}, nil)
+		appException.ErrorType = RecordNotFound
+
+		return appException
+	}
+
+	driverErr := parse(err)
+
+	if driverErr == nil {
+		return exception.NewInternalServerAppException(&dbError{"database error", err}, nil)
+	}
+
+	var appException *exception.AppException
+
+	switch driverErr.errorType {
+	case UniqueViolation:
+		appException = exception.NewAppException(http.StatusConflict, &dbError{"record already exists", err}, map[string]any{})
+	case ForeignKeyViolation:
+		appException = exception.NewAppException(http.StatusUnprocessableEntity, &dbError{"related record not found or still referenced", err}, map[string]any{})
+	case SerializationFailure:
+		appException = exception.NewAppException(http.StatusServiceUnavailable, &dbError{"concurrent update, retry the request", err}, map[string]any{
+			"retryable": true,
+		})
+	}
+
+	appException.ErrorType = driverErr.errorType
+
+	if driverErr.constraint != "" {
+		appException.Context["constraint"] = driverErr.constraint
+	}
+
+	if driverErr.table != "" {
+		appException.Context["table"] = driverErr.table
+	}
+
+	return appException
+}
+
+// IsNotFound - запись не найдена
+func IsNotFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
+
+// IsUniqueViolation - нарушение уникальности
+func IsUniqueViolation(err error) bool {
+	driverErr := parse(err)
+
+	return driverErr != nil && driverErr.errorType == UniqueViolation
+}
+
+// IsForeignKeyViolation - нарушение внешнего ключа
+func IsForeignKeyViolation(err error) bool {
+	driverErr := parse(err)
+
+	return driverErr != nil && driverErr.errorType == ForeignKeyViolation
+}
+
+// IsRetryable - конфликт сериализации, deadlock или таймаут блокировки: транзакцию можно повторить
+func IsRetryable(err error) bool {
+	driverErr := parse(err)
+
+	return driverErr != nil && driverErr.errorType == SerializationFailure
+}
+
+// parse - разбирает ошибку драйвера, nil если ошибка не относится к известным
+func parse(err error) *driverError {
+	if err == nil {
+		return nil
+	}
+
+	var pgErr *pgconn.PgError
+
+	if errors.As(err, &pgErr) {
+		return parsePostgres(pgErr)
+	}
+
+	var mysqlErr *mysql.MySQLError
+
+	if errors.As(err, &mysqlErr) {
+		return parseMySql(mysqlErr)
+	}
+
+	var mssqlErr mssql.Error
+
+	if errors.As(err, &mssqlErr) {
+		return parseMsSql(mssqlErr)
+	}
+
+	// ошибки, которые переводит сам gorm при TranslateError: true
+	switch {
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return &driverError{errorType: UniqueViolation}
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return &driverError{errorType: ForeignKeyViolation}
+	}
+
+	return nil
+}
+
+func parsePostgres(err *pgconn.PgError) *driverError {
+	result := &driverError{constraint: err.ConstraintName, table: err.TableName}
+
+	switch err.Code {
+	case pgUniqueViolation:
+		result.errorType = UniqueViolation
+	case pgForeignKeyViolation:
+		result.errorType = ForeignKeyViolation
+	case pgSerializationFailure, pgDeadlockDetected:
+		result.errorType = SerializationFailure
+	default:
+		return nil
+	}
+
+	return result
+}
+
+func parseMySql(err *mysql.MySQLError) *driverError {
+	switch err.Number {
+	case mysqlDuplicateEntry:
+		return &driverError{errorType: UniqueViolation, constraint: submatch(mysqlKeyRegexp, err.Message)}
+	case mysqlRowIsReferenced, mysqlNoReferencedRow:
+		return &driverError{errorType: ForeignKeyViolation, constraint: submatch(mysqlConstraintRegexp, err.Message)}
+	case mysqlDeadlock, mysqlLockWaitTimeout:
+		return &driverError{errorType: SerializationFailure}
+	}
+
+	return nil
+}
+
+func parseMsSql(err mssql.Error) *driverError {
+	switch err.Number {
+	case mssqlUniqueConstraint, mssqlUniqueIndex:
+		return &driverError{errorType: UniqueViolation, constraint: submatch(mssqlConstraintRegexp, err.Message)}
+	case mssqlConstraint:
+		// 547 - любое ограничение, в том числе CHECK
+		if !strings.Contains(err.Message, "FOREIGN KEY") {
+			return nil
+		}
+
+		return &driverError{errorType: ForeignKeyViolation, constraint: submatch(mssqlConstraintRegexp, err.Message)}
+	case mssqlDeadlock, mssqlSnapshotConflict:
+		return &driverError{errorType: SerializationFailure}
+	}
+
+	return nil
+}
+
+func submatch(re *regexp.Regexp, message string) string {
+	if match := re.FindStringSubmatch(message); len(match) > 1 {
+		return match[1]
+	}
+
+	return ""
+}
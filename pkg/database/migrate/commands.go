@@ -0,0 +1,83 @@
+package migrate
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/ZhanibekTau/go-sdk/pkg/app"
+	"text/tabwriter"
+)
+
+// RegisterCommands - консольные команды миграций для app.RunConsole, обычно в PrepareConsole:
+//
+//	migrate:up [--dry-run] [--out-of-order]  применить новые миграции
+//	migrate:down [--steps=1] [--dry-run]     откатить последние миграции
+//	migrate:status                           состояние миграций
+func RegisterCommands(application *app.App, migrator *Migrator) {
+	application.RegisterCommand(app.Command{
+		Name:        "migrate:up",
+		Description: "apply pending migrations [--dry-run] [--out-of-order]",
+		Run: func(_ *app.App, args []string) error {
+			flags := flag.NewFlagSet("migrate:up", flag.ContinueOnError)
+			dryRun := flags.Bool("dry-run", false, "print SQL without executing")
+			outOfOrder := flags.Bool("out-of-order", false, "apply migrations older than the last applied one")
+
+			if err := flags.Parse(args); err != nil {
+				return err
+			}
+
+			_, err := migrator.SetDryRun(*dryRun).SetOutOfOrder(*outOfOrder).Up(context.Background())
+
+			return err
+		},
+	})
+
+	application.RegisterCommand(app.Command{
+		Name:        "migrate:down",
+		Description: "roll back last migrations [--steps=1] [--dry-run]",
+		Run: func(_ *app.App, args []string) error {
+			flags := flag.NewFlagSet("migrate:down", flag.ContinueOnError)
+			steps := flags.Int("steps", 1, "number of migrations to roll back")
+			dryRun := flags.Bool("dry-run", false, "print SQL without executing")
+
+			if err := flags.Parse(args); err != nil {
+				return err
+			}
+
+			_, err := migrator.SetDryRun(*dryRun).Down(context.Background(), *steps)
+
+			return err
+		},
+	})
+
+	application.RegisterCommand(app.Command{
+		Name:        "migrate:status",
+		Description: "show applied and pending migrations",
+		Run: func(_ *app.App, _ []string) error {
+			statuses, err := migrator.Status(context.Background())
+
+			if err != nil {
+				return err
+			}
+
+			writer := tabwriter.NewWriter(migrator.output, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(writer, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+
+			for _, status := range statuses {
+				state, appliedAt := "pending", ""
+
+				if status.Applied {
+					state, appliedAt = "applied", status.AppliedAt.Format("2006-01-02 15:04:05")
+				}
+
+				if status.Missing {
+					state = "missing in code"
+				}
+
+				_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+			}
+
+			return writer.Flush()
+		},
+	})
+}
@@ -0,0 +1,110 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+// ErrLockTimeout - блокировку держит другая реплика дольше lockTimeout
+var ErrLockTimeout = errors.New("migrate: timeout waiting for migration lock")
+
+// lockQueries - попытка взять блокировку без ожидания и освобождение, в рамках одного соединения
+type lockQueries struct {
+	tryLock string
+	unlock  string
+	key     any
+}
+
+// lockQueriesFor - блокировка на уровне сессии БД, чтобы миграции выполняла только одна реплика
+func lockQueriesFor(dialect string, table string) (*lockQueries, error) {
+	name := "migrate:" + table
+
+	switch dialect {
+	case "postgres":
+		hash := fnv.New64a()
+		hash.Write([]byte(name))
+
+		return &lockQueries{
+			tryLock: "SELECT pg_try_advisory_lock($1)",
+			unlock:  "SELECT pg_advisory_unlock($1)",
+			key:     int64(hash.Sum64()),
+		}, nil
+	case "mysql":
+		return &lockQueries{
+			tryLock: "SELECT COALESCE(GET_LOCK(?, 0), 0) = 1",
+			unlock:  "SELECT RELEASE_LOCK(?)",
+			key:     name,
+		}, nil
+	case "sqlserver":
+		return &lockQueries{
+			tryLock: "DECLARE @result int; EXEC @result = sp_getapplock @Resource = @p1, @LockMode = 'Exclusive', " +
+				"@LockOwner = 'Session', @LockTimeout = 0; SELECT CAST(CASE WHEN @result >= 0 THEN 1 ELSE 0 END AS bit)",
+			unlock: "EXEC sp_releaseapplock @Resource = @p1, @LockOwner = 'Session'",
+			key:    name,
+		}, nil
+	case "sqlite":
+		// база Sqlite локальная, запись и так выполняет одно соединение за раз
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("migrate: locking is not supported for %s", dialect)
+}
+
+// acquireLock - ждет блокировку до timeout, возвращает функцию освобождения
+func acquireLock(ctx context.Context, db *sql.DB, dialect string, table string, timeout time.Duration) (func(), error) {
+	queries, err := lockQueriesFor(dialect, table)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if queries == nil {
+		return func() {}, nil
+	}
+
+	// блокировка сессионная: взять и освободить ее нужно в одном соединении
+	conn, err := db.Conn(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+		var locked bool
+
+		if err = conn.QueryRowContext(ctx, queries.tryLock, queries.key).Scan(&locked); err != nil {
+			_ = conn.Close()
+
+			return nil, err
+		}
+
+		if locked {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			_ = conn.Close()
+
+			return nil, ErrLockTimeout
+		}
+
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+
+			return nil, ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+
+	return func() {
+		_, _ = conn.ExecContext(context.Background(), queries.unlock, queries.key)
+		_ = conn.Close()
+	}, nil
+}
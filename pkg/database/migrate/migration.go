@@ -0,0 +1,257 @@
+package migrate
+
+import (
+	"bufio"
+	"fmt"
+	"gorm.io/gorm"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Директивы в SQL файлах миграций
+const (
+	// directiveNoTransaction - первая строка файла: миграция выполняется без транзакции (например CREATE INDEX CONCURRENTLY)
+	directiveNoTransaction = "-- migrate:no-transaction"
+	// directiveStatementBegin, directiveStatementEnd - блок выполняется одним запросом, точки с запятой внутри не разделяют
+	// запросы (функции, триггеры)
+	directiveStatementBegin = "-- migrate:statement-begin"
+	directiveStatementEnd   = "-- migrate:statement-end"
+)
+
+// dollarQuoteRegexp - открывающая метка строки в долларах PostgreSQL: $$ или $tag$
+var dollarQuoteRegexp = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)?\$`)
+
+// sqlFileRegexp - <версия>_<имя>.up.sql или <версия>_<имя>.down.sql, версия - число, например 20240131120000
+var sqlFileRegexp = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration - миграция схемы. Up и Down получают транзакцию (или соединение при NoTransaction),
+// Down может быть nil - такую миграцию нельзя откатить
+type Migration struct {
+	// Version - число, определяет порядок миграций, например 20240131120000
+	Version string
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+	// NoTransaction - выполнять без транзакции
+	NoTransaction bool
+	// upSql, downSql - запросы SQL миграции, нужны для dry-run
+	upSql   []string
+	downSql []string
+}
+
+func (m *Migration) String() string {
+	return m.Version + "_" + m.Name
+}
+
+// loadFS - SQL миграции из каталога dir файловой системы fsys (например embed.FS)
+func loadFS(fsys fs.FS, dir string) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := map[string]*Migration{}
+
+	for _, entry := range entries {
+		match := sqlFileRegexp.FindStringSubmatch(entry.Name())
+
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		content, rErr := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+
+		if rErr != nil {
+			return nil, rErr
+		}
+
+		version, name, direction := match[1], match[2], match[3]
+		migration, ok := migrations[version]
+
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			migrations[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("migrate: version %s has different names: %s and %s", version, migration.Name, name)
+		}
+
+		statements, noTransaction, pErr := parseSql(string(content))
+
+		if pErr != nil {
+			return nil, fmt.Errorf("migrate: %s: %w", entry.Name(), pErr)
+		}
+
+		if direction == "up" {
+			migration.upSql = statements
+			migration.NoTransaction = noTransaction
+			migration.Up = execStatements(statements)
+		} else {
+			migration.downSql = statements
+			migration.Down = execStatements(statements)
+		}
+	}
+
+	result := make([]*Migration, 0, len(migrations))
+
+	for _, migration := range migrations {
+		if migration.Up == nil {
+			return nil, fmt.Errorf("migrate: %s has no up file", migration)
+		}
+
+		result = append(result, migration)
+	}
+
+	sortMigrations(result)
+
+	return result, nil
+}
+
+// parseSql - разбивает файл на запросы: запрос заканчивается строкой с точкой с запятой в конце,
+// кроме блоков statement-begin/statement-end. Точка с запятой внутри строк ('...', "...", $$...$$, $tag$...$tag$)
+// и блочных комментариев запрос не завершает. Кавычка в строке экранируется удвоением кавычки, экранирование обратным
+// слешем (MySQL) не распознается - такие запросы оборачиваются в statement-begin/statement-end.
+// Драйверы не везде выполняют несколько запросов за раз
+func parseSql(content string) ([]string, bool, error) {
+	var (
+		statements    []string
+		current       strings.Builder
+		inBlock       bool
+		noTransaction bool
+		first         = true
+		// quote - закрывающая последовательность незакрытой строки или комментария, пусто - вне строки
+		quote string
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); statement != "" {
+			statements = append(statements, statement)
+		}
+
+		current.Reset()
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case first && trimmed == directiveNoTransaction:
+			noTransaction = true
+		case trimmed == directiveStatementBegin:
+			flush()
+			inBlock = true
+		case trimmed == directiveStatementEnd:
+			if !inBlock {
+				return nil, false, fmt.Errorf("%s without %s", directiveStatementEnd, directiveStatementBegin)
+			}
+
+			flush()
+			inBlock = false
+		case !inBlock && quote == "" && (trimmed == "" || strings.HasPrefix(trimmed, "--")):
+			// пустые строки и комментарии между запросами
+		default:
+			current.WriteString(line)
+			current.WriteString("\n")
+
+			if inBlock {
+				break
+			}
+
+			if quote = scanQuote(line, quote); quote == "" && strings.HasSuffix(trimmed, ";") {
+				flush()
+			}
+		}
+
+		if trimmed != "" {
+			first = false
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, false, err
+	}
+
+	if inBlock {
+		return nil, false, fmt.Errorf("%s without %s", directiveStatementBegin, directiveStatementEnd)
+	}
+
+	flush()
+
+	return statements, noTransaction, nil
+}
+
+// scanQuote - закрывающая последовательность строки или комментария, незакрытых в конце line. quote - незакрытая
+// с предыдущих строк
+func scanQuote(line string, quote string) string {
+	for i := 0; i < len(line); {
+		rest := line[i:]
+
+		if quote != "" {
+			switch {
+			case quote == "'" && strings.HasPrefix(rest, "''"):
+				i += 2
+			case strings.HasPrefix(rest, quote):
+				i += len(quote)
+				quote = ""
+			default:
+				i++
+			}
+
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(rest, "--"):
+			return ""
+		case strings.HasPrefix(rest, "/*"):
+			quote = "*/"
+			i += 2
+		case rest[0] == '\'' || rest[0] == '"':
+			quote = rest[:1]
+			i++
+		case rest[0] == '$' && dollarQuoteRegexp.MatchString(rest):
+			quote = dollarQuoteRegexp.FindString(rest)
+			i += len(quote)
+		default:
+			i++
+		}
+	}
+
+	return quote
+}
+
+func execStatements(statements []string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// sortMigrations - по возрастанию версии как числа
+func sortMigrations(migrations []*Migration) {
+	sort.Slice(migrations, func(i, j int) bool {
+		return compareVersions(migrations[i].Version, migrations[j].Version) < 0
+	})
+}
+
+func compareVersions(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+
+	return strings.Compare(a, b)
+}
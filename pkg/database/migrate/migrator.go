@@ -0,0 +1,420 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"time"
+)
+
+// NewMigrator - миграции схемы для db, поддерживаются все драйверы database.GetGormConnection
+func NewMigrator(db *gorm.DB) *Migrator {
+	return &Migrator{
+		db:          db,
+		table:       "schema_migrations",
+		lockTimeout: time.Minute,
+		output:      os.Stdout,
+	}
+}
+
+// Migrator - применяет и откатывает миграции по таблице истории. На время Up и Down берется блокировка в БД,
+// поэтому при одновременном старте нескольких реплик миграции выполнит только одна
+type Migrator struct {
+	db          *gorm.DB
+	migrations  []*Migration
+	table       string
+	lockTimeout time.Duration
+	output      io.Writer
+	dryRun      bool
+	outOfOrder  bool
+}
+
+// historyRecord - запись таблицы истории миграций
+type historyRecord struct {
+	Version   string `gorm:"primaryKey;size:64"`
+	Name      string `gorm:"size:255"`
+	AppliedAt time.Time
+}
+
+// MigrationStatus - состояние миграции
+type MigrationStatus struct {
+	Version   string     `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at"`
+	// Missing - миграция применена, но ее нет в коде
+	Missing bool `json:"missing"`
+}
+
+// SetTable - таблица истории миграций, по умолчанию schema_migrations
+func (m *Migrator) SetTable(table string) *Migrator {
+	m.table = table
+
+	return m
+}
+
+// SetLockTimeout - сколько ждать блокировку, которую держит другая реплика
+func (m *Migrator) SetLockTimeout(timeout time.Duration) *Migrator {
+	m.lockTimeout = timeout
+
+	return m
+}
+
+// SetOutput - куда писать ход миграций и SQL в режиме dry-run
+func (m *Migrator) SetOutput(output io.Writer) *Migrator {
+	m.output = output
+
+	return m
+}
+
+// SetDryRun - Up и Down только выводят SQL, ничего не выполняя. Для Go миграций выводится SQL,
+// построенный gorm без выполнения, запросы чтения внутри миграции при этом ничего не вернут
+func (m *Migrator) SetDryRun(dryRun bool) *Migrator {
+	m.dryRun = dryRun
+
+	return m
+}
+
+// SetOutOfOrder - Up применяет и миграции старше последней примененной (например, из долгой ветки, влитой после
+// более новых). По умолчанию Up в этом случае возвращает ошибку, ничего не применяя
+func (m *Migrator) SetOutOfOrder(outOfOrder bool) *Migrator {
+	m.outOfOrder = outOfOrder
+
+	return m
+}
+
+// AddFS - SQL миграции из каталога dir, например из embed.FS:
+//
+//	//go:embed migrations/*.sql
+//	var migrations embed.FS
+//
+//	err := migrator.AddFS(migrations, "migrations")
+//
+// Файлы: 20240131120000_create_users.up.sql и 20240131120000_create_users.down.sql
+func (m *Migrator) AddFS(fsys fs.FS, dir string) error {
+	migrations, err := loadFS(fsys, dir)
+
+	if err != nil {
+		return err
+	}
+
+	return m.add(migrations)
+}
+
+// Add - Go миграции
+func (m *Migrator) Add(migrations ...Migration) error {
+	list := make([]*Migration, 0, len(migrations))
+
+	for i := range migrations {
+		if migrations[i].Version == "" || migrations[i].Up == nil {
+			return fmt.Errorf("migrate: migration %s must have version and up", migrations[i].String())
+		}
+
+		list = append(list, &migrations[i])
+	}
+
+	return m.add(list)
+}
+
+func (m *Migrator) add(migrations []*Migration) error {
+	known := make(map[string]bool, len(m.migrations))
+
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+	}
+
+	for _, migration := range migrations {
+		if known[migration.Version] {
+			return fmt.Errorf("migrate: duplicate version %s", migration.Version)
+		}
+
+		known[migration.Version] = true
+	}
+
+	m.migrations = append(m.migrations, migrations...)
+	sortMigrations(m.migrations)
+
+	return nil
+}
+
+// Up - применяет все новые миграции по порядку, возвращает примененные
+func (m *Migrator) Up(ctx context.Context) ([]string, error) {
+	release, err := m.lock(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
+	history, err := m.history(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]*Migration, 0)
+	latest := ""
+
+	for version := range history {
+		if compareVersions(version, latest) > 0 {
+			latest = version
+		}
+	}
+
+	for _, migration := range m.migrations {
+		if _, applied := history[migration.Version]; applied {
+			continue
+		}
+
+		if !m.outOfOrder && compareVersions(migration.Version, latest) < 0 {
+			return nil, fmt.Errorf("migrate: %s is older than the last applied version %s, apply it with SetOutOfOrder", migration, latest)
+		}
+
+		pending = append(pending, migration)
+	}
+
+	result := make([]string, 0, len(pending))
+
+	for _, migration := range pending {
+		if err = m.apply(ctx, migration, true); err != nil {
+			return result, fmt.Errorf("migrate: %s: %w", migration, err)
+		}
+
+		result = append(result, migration.String())
+	}
+
+	if len(result) == 0 {
+		_, _ = fmt.Fprintln(m.output, "nothing to migrate")
+	}
+
+	return result, nil
+}
+
+// Down - откатывает steps (минимум одну) последних примененных миграций, возвращает откаченные
+func (m *Migrator) Down(ctx context.Context, steps int) ([]string, error) {
+	if steps < 1 {
+		steps = 1
+	}
+
+	release, err := m.lock(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
+	history, err := m.history(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]string, 0, len(history))
+
+	for version := range history {
+		versions = append(versions, version)
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return compareVersions(versions[i], versions[j]) > 0
+	})
+
+	if steps < len(versions) {
+		versions = versions[:steps]
+	}
+
+	result := make([]string, 0, len(versions))
+
+	for _, version := range versions {
+		migration := m.find(version)
+
+		if migration == nil {
+			return result, fmt.Errorf("migrate: applied migration %s_%s not found in code", version, history[version].Name)
+		}
+
+		if migration.Down == nil {
+			return result, fmt.Errorf("migrate: %s has no down migration", migration)
+		}
+
+		if err = m.apply(ctx, migration, false); err != nil {
+			return result, fmt.Errorf("migrate: %s: %w", migration, err)
+		}
+
+		result = append(result, migration.String())
+	}
+
+	return result, nil
+}
+
+// Status - все миграции кода и примененные миграции, которых нет в коде, по порядку версий
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	history, err := m.history(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]MigrationStatus, 0, len(m.migrations))
+
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+
+		if record, ok := history[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &record.AppliedAt
+			delete(history, migration.Version)
+		}
+
+		result = append(result, status)
+	}
+
+	for _, record := range history {
+		appliedAt := record.AppliedAt
+		result = append(result, MigrationStatus{Version: record.Version, Name: record.Name, Applied: true, AppliedAt: &appliedAt, Missing: true})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return compareVersions(result[i].Version, result[j].Version) < 0
+	})
+
+	return result, nil
+}
+
+// apply - миграция и запись в истории в одной транзакции (если драйвер поддерживает транзакционный DDL)
+func (m *Migrator) apply(ctx context.Context, migration *Migration, up bool) error {
+	action, fn, statements := "migrating", migration.Up, migration.upSql
+
+	if !up {
+		action, fn, statements = "rolling back", migration.Down, migration.downSql
+	}
+
+	if m.dryRun {
+		return m.printDryRun(ctx, migration, action, fn, statements)
+	}
+
+	_, _ = fmt.Fprintf(m.output, "%s: %s\n", action, migration)
+	start := time.Now()
+
+	run := func(tx *gorm.DB) error {
+		if err := fn(tx); err != nil {
+			return err
+		}
+
+		if up {
+			return tx.Table(m.table).Create(&historyRecord{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+		}
+
+		return tx.Table(m.table).Where("version = ?", migration.Version).Delete(&historyRecord{}).Error
+	}
+
+	var err error
+
+	if migration.NoTransaction {
+		err = run(m.db.WithContext(ctx))
+	} else {
+		err = m.db.WithContext(ctx).Transaction(run)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(m.output, "done: %s (%s)\n", migration, time.Since(start).Round(time.Millisecond))
+
+	return nil
+}
+
+func (m *Migrator) printDryRun(ctx context.Context, migration *Migration, action string, fn func(tx *gorm.DB) error, statements []string) error {
+	_, _ = fmt.Fprintf(m.output, "-- %s (dry run): %s\n", action, migration)
+
+	if statements != nil {
+		for _, statement := range statements {
+			_, _ = fmt.Fprintln(m.output, statement)
+		}
+
+		return nil
+	}
+
+	return fn(m.db.Session(&gorm.Session{DryRun: true, Context: ctx, Logger: sqlPrinter{m.output}}))
+}
+
+// history - примененные миграции, пусто если таблицы истории еще нет
+func (m *Migrator) history(ctx context.Context) (map[string]historyRecord, error) {
+	// история читается с основной БД, реплика может отставать
+	db := m.db.WithContext(ctx).Clauses(dbresolver.Write)
+	result := map[string]historyRecord{}
+
+	if !db.Migrator().HasTable(m.table) {
+		if m.dryRun {
+			return result, nil
+		}
+
+		if err := db.Table(m.table).AutoMigrate(&historyRecord{}); err != nil {
+			return nil, err
+		}
+	}
+
+	var records []historyRecord
+
+	if err := db.Table(m.table).Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		result[record.Version] = record
+	}
+
+	return result, nil
+}
+
+func (m *Migrator) lock(ctx context.Context) (func(), error) {
+	if m.dryRun {
+		return func() {}, nil
+	}
+
+	sqlDb, err := m.db.DB()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return acquireLock(ctx, sqlDb, m.db.Dialector.Name(), m.table, m.lockTimeout)
+}
+
+func (m *Migrator) find(version string) *Migration {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration
+		}
+	}
+
+	return nil
+}
+
+// sqlPrinter - логгер gorm для dry-run: выводит построенный SQL
+type sqlPrinter struct {
+	output io.Writer
+}
+
+func (p sqlPrinter) LogMode(gormLogger.LogLevel) gormLogger.Interface {
+	return p
+}
+
+func (p sqlPrinter) Info(context.Context, string, ...interface{}) {}
+
+func (p sqlPrinter) Warn(context.Context, string, ...interface{}) {}
+
+func (p sqlPrinter) Error(context.Context, string, ...interface{}) {}
+
+func (p sqlPrinter) Trace(_ context.Context, _ time.Time, fc func() (sql string, rowsAffected int64), _ error) {
+	sql, _ := fc()
+	_, _ = fmt.Fprintln(p.output, sql+";")
+}
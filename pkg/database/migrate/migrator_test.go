@@ -0,0 +1,226 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/ZhanibekTau/go-sdk/pkg/database/dbtest"
+	"gorm.io/gorm"
+	"io"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+var testMigrations = fstest.MapFS{
+	"migrations/1_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);\n")},
+	"migrations/1_create_users.down.sql": {Data: []byte("DROP TABLE users;\n")},
+	"migrations/2_add_email.up.sql": {Data: []byte(`-- добавляет email
+ALTER TABLE users ADD COLUMN email TEXT;
+CREATE INDEX users_email ON users (email);
+`)},
+	"migrations/2_add_email.down.sql": {Data: []byte("DROP INDEX users_email;\nALTER TABLE users DROP COLUMN email;\n")},
+	"migrations/readme.md":            {Data: []byte("not a migration")},
+}
+
+func hasColumn(db *gorm.DB, table string, column string) bool {
+	return db.Migrator().HasColumn(table, column)
+}
+
+func TestMigratorUpAndDown(t *testing.T) {
+	db := dbtest.NewTestDB(t)
+	migrator := NewMigrator(db).SetOutput(io.Discard)
+
+	if err := migrator.AddFS(testMigrations, "migrations"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	applied, err := migrator.Up(ctx)
+
+	if err != nil || fmt.Sprint(applied) != "[1_create_users 2_add_email]" {
+		t.Fatalf("up: %v, %v", applied, err)
+	}
+
+	if !hasColumn(db, "users", "email") {
+		t.Fatal("email column is not created")
+	}
+
+	// повторный Up ничего не применяет
+	if applied, err = migrator.Up(ctx); err != nil || len(applied) != 0 {
+		t.Fatalf("second up: %v, %v", applied, err)
+	}
+
+	rolledBack, err := migrator.Down(ctx, 1)
+
+	if err != nil || fmt.Sprint(rolledBack) != "[2_add_email]" {
+		t.Fatalf("down: %v, %v", rolledBack, err)
+	}
+
+	if hasColumn(db, "users", "email") || !db.Migrator().HasTable("users") {
+		t.Fatal("down did not roll back only the last migration")
+	}
+
+	statuses, err := migrator.Status(ctx)
+
+	if err != nil || len(statuses) != 2 || !statuses[0].Applied || statuses[1].Applied {
+		t.Fatalf("status: %+v, %v", statuses, err)
+	}
+}
+
+func TestMigratorFailedMigrationIsNotRecorded(t *testing.T) {
+	db := dbtest.NewTestDB(t)
+	migrator := NewMigrator(db).SetOutput(io.Discard)
+
+	if err := migrator.AddFS(testMigrations, "migrations"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	err := migrator.Add(Migration{
+		Version: "3",
+		Name:    "broken",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Exec("CREATE TABLE orders (id INTEGER PRIMARY KEY)").Error; err != nil {
+				return err
+			}
+
+			return tx.Exec("INSERT INTO missing_table VALUES (1)").Error
+		},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := migrator.Up(ctx)
+
+	if err == nil || fmt.Sprint(applied) != "[1_create_users 2_add_email]" {
+		t.Fatalf("up: %v, %v", applied, err)
+	}
+
+	// миграция и запись в истории в одной транзакции
+	if db.Migrator().HasTable("orders") {
+		t.Fatal("failed migration is not rolled back")
+	}
+
+	statuses, err := migrator.Status(ctx)
+
+	if err != nil || statuses[2].Applied {
+		t.Fatalf("status: %+v, %v", statuses, err)
+	}
+}
+
+func TestMigratorDryRun(t *testing.T) {
+	db := dbtest.NewTestDB(t)
+	migrator := NewMigrator(db).SetOutput(io.Discard)
+
+	if err := migrator.AddFS(testMigrations, "migrations"); err != nil {
+		t.Fatal(err)
+	}
+
+	output := &bytes.Buffer{}
+	migrator.SetOutput(output).SetDryRun(true)
+
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if db.Migrator().HasTable("users") {
+		t.Fatal("dry run executed migration")
+	}
+
+	if !strings.Contains(output.String(), "CREATE TABLE users") || !strings.Contains(output.String(), "CREATE INDEX users_email") {
+		t.Fatalf("dry run output: %s", output.String())
+	}
+}
+
+func TestMigratorRejectsDuplicateVersion(t *testing.T) {
+	migrator := NewMigrator(dbtest.NewTestDB(t))
+
+	err := migrator.Add(
+		Migration{Version: "1", Name: "first", Up: func(tx *gorm.DB) error { return nil }},
+		Migration{Version: "1", Name: "again", Up: func(tx *gorm.DB) error { return nil }},
+	)
+
+	if err == nil || !strings.Contains(err.Error(), "duplicate version 1") {
+		t.Fatalf("got %v, want duplicate version error", err)
+	}
+}
+
+func TestMigratorRejectsOutOfOrderVersion(t *testing.T) {
+	db := dbtest.NewTestDB(t)
+	migrator := NewMigrator(db).SetOutput(io.Discard)
+	ctx := context.Background()
+	created := false
+
+	if err := migrator.Add(Migration{Version: "2", Name: "second", Up: func(tx *gorm.DB) error { return nil }}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// миграция из ветки, влитой после версии 2
+	if err := migrator.Add(Migration{Version: "1", Name: "first", Up: func(tx *gorm.DB) error { created = true; return nil }}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := migrator.Up(ctx); err == nil || !strings.Contains(err.Error(), "older than the last applied version 2") || created {
+		t.Fatalf("got %v (applied %v), want out of order error", err, created)
+	}
+
+	if applied, err := migrator.SetOutOfOrder(true).Up(ctx); err != nil || fmt.Sprint(applied) != "[1_first]" {
+		t.Fatalf("out of order up: %v, %v", applied, err)
+	}
+}
+
+func TestParseSqlQuotedSemicolons(t *testing.T) {
+	statements, _, err := parseSql(`INSERT INTO notes (body) VALUES ('first;
+-- not a comment;
+it''s;');
+CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+/* comment;
+*/ SELECT 1;
+`)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(statements) != 3 || !strings.HasSuffix(statements[0], "it''s;');") || !strings.HasSuffix(statements[1], "plpgsql;") {
+		t.Fatalf("statements %q", statements)
+	}
+}
+
+func TestParseSqlStatementBlock(t *testing.T) {
+	statements, noTransaction, err := parseSql(`-- migrate:no-transaction
+CREATE TABLE a (id INTEGER);
+-- migrate:statement-begin
+CREATE TRIGGER a_insert AFTER INSERT ON a
+BEGIN
+    SELECT 1;
+END;
+-- migrate:statement-end
+`)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !noTransaction || len(statements) != 2 || !strings.HasSuffix(statements[1], "END;") {
+		t.Fatalf("no transaction %v, statements %q", noTransaction, statements)
+	}
+
+	if _, _, err = parseSql("-- migrate:statement-begin\nSELECT 1;\n"); err == nil {
+		t.Fatal("unclosed statement block is accepted")
+	}
+}
@@ -21,6 +21,7 @@ func NewRedisHelper[E interface{}](redisClient *redis.Client) *RedisHelper[E] {
 type RedisHelper[E interface{}] struct {
 	redisClient *redis.Client
 	appInfo     *config.AppInfo
+	ctx         context.Context
 	result      E
 }
 
@@ -31,9 +32,41 @@ func (redisHelper *RedisHelper[E]) SetRequestData(appInfo *config.AppInfo) *Redi
 	return redisHelper
 }
 
+// WithContext - копия хелпера с контекстом для команд редиса, данные запроса для логов берутся из него
+// (см. config.WithAppInfo). Общий хелпер не меняется, поэтому его можно использовать из параллельных запросов
+func (redisHelper *RedisHelper[E]) WithContext(ctx context.Context) *RedisHelper[E] {
+	helper := *redisHelper
+	helper.ctx = ctx
+
+	return &helper
+}
+
+func (redisHelper *RedisHelper[E]) context() context.Context {
+	if redisHelper.ctx != nil {
+		return redisHelper.ctx
+	}
+
+	return context.Background()
+}
+
+// log - лог операции с данными запроса, если они есть
+func (redisHelper *RedisHelper[E]) log(message string) {
+	appInfo := redisHelper.appInfo
+
+	if appInfo == nil {
+		appInfo = config.AppInfoFromContext(redisHelper.ctx)
+	}
+
+	if appInfo != nil {
+		logger.FormattedLogWithAppInfo(appInfo, message)
+	} else {
+		println(message)
+	}
+}
+
 // GetByModel Возвращает значение по ключу
 func (redisHelper *RedisHelper[E]) GetByModel(key string) (*E, error) {
-	ctx := context.Background()
+	ctx := redisHelper.context()
 	val, err := redisHelper.redisClient.Get(ctx, key).Result()
 
 	if err != nil && !errors.Is(err, redis.Nil) {
@@ -50,11 +83,7 @@ func (redisHelper *RedisHelper[E]) GetByModel(key string) (*E, error) {
 		return nil, unMarshErr
 	}
 
-	if redisHelper.appInfo != nil {
-		logger.FormattedLogWithAppInfo(redisHelper.appInfo, "GOT DATA FROM CACHE: "+val)
-	} else {
-		println("GOT DATA FROM CACHE: " + val)
-	}
+	redisHelper.log("GOT DATA FROM CACHE: " + val)
 
 	return &redisHelper.result, nil
 }
@@ -68,25 +97,21 @@ func (redisHelper *RedisHelper[E]) SetByModel(key string, model *E, ttl time.Dur
 		return err
 	}
 
-	ctx := context.Background()
+	ctx := redisHelper.context()
 	err = redisHelper.redisClient.Set(ctx, key, jsonModel, ttl).Err()
 
 	if err != nil {
 		return err
 	}
 
-	if redisHelper.appInfo != nil {
-		logger.FormattedLogWithAppInfo(redisHelper.appInfo, "SET DATA TO CACHE: "+string(jsonModel))
-	} else {
-		println("SET DATA TO CACHE: " + string(jsonModel))
-	}
+	redisHelper.log("SET DATA TO CACHE: " + string(jsonModel))
 
 	return nil
 }
 
 // GetString Возвращает значение по ключу
 func (redisHelper *RedisHelper[E]) GetString(key string) (string, error) {
-	ctx := context.Background()
+	ctx := redisHelper.context()
 	val, err := redisHelper.redisClient.Get(ctx, key).Result()
 
 	if err != nil && !errors.Is(err, redis.Nil) {
@@ -97,29 +122,21 @@ func (redisHelper *RedisHelper[E]) GetString(key string) (string, error) {
 		return "", nil
 	}
 
-	if redisHelper.appInfo != nil {
-		logger.FormattedLogWithAppInfo(redisHelper.appInfo, "GOT DATA FROM CACHE: "+val)
-	} else {
-		println("GOT DATA FROM CACHE: " + val)
-	}
+	redisHelper.log("GOT DATA FROM CACHE: " + val)
 
 	return val, nil
 }
 
 // SetString Записывает значение по ключу
 func (redisHelper *RedisHelper[E]) SetString(key string, string string, ttl time.Duration) error {
-	ctx := context.Background()
+	ctx := redisHelper.context()
 	err := redisHelper.redisClient.Set(ctx, key, string, ttl).Err()
 
 	if err != nil {
 		return err
 	}
 
-	if redisHelper.appInfo != nil {
-		logger.FormattedLogWithAppInfo(redisHelper.appInfo, "SET DATA TO CACHE: "+string)
-	} else {
-		println("SET DATA TO CACHE: " + string)
-	}
+	redisHelper.log("SET DATA TO CACHE: " + string)
 
 	return nil
 }
@@ -127,7 +144,7 @@ func (redisHelper *RedisHelper[E]) SetString(key string, string string, ttl time
 // GetArrayOfPointerModels Возвращает list по ключу
 func (redisHelper *RedisHelper[E]) GetArrayOfPointerModels(key string) ([]*E, error) {
 	resultArr := make([]*E, 0)
-	ctx := context.Background()
+	ctx := redisHelper.context()
 	val, err := redisHelper.redisClient.Get(ctx, key).Result()
 
 	if err != nil && !errors.Is(err, redis.Nil) {
@@ -144,11 +161,7 @@ func (redisHelper *RedisHelper[E]) GetArrayOfPointerModels(key string) ([]*E, er
 		return nil, unMarshErr
 	}
 
-	if redisHelper.appInfo != nil {
-		logger.FormattedLogWithAppInfo(redisHelper.appInfo, "GOT LIST FROM CACHE: "+val)
-	} else {
-		println("GOT LIST FROM CACHE: " + val)
-	}
+	redisHelper.log("GOT LIST FROM CACHE: " + val)
 
 	if len(resultArr) == 0 {
 		return nil, nil
@@ -170,7 +183,7 @@ func (redisHelper *RedisHelper[E]) SetArrayOfPointerModels(key string, models []
 		return err
 	}
 
-	ctx := context.Background()
+	ctx := redisHelper.context()
 
 	rErr := redisHelper.redisClient.Set(ctx, key, str, ttl).Err()
 
@@ -178,11 +191,7 @@ func (redisHelper *RedisHelper[E]) SetArrayOfPointerModels(key string, models []
 		return rErr
 	}
 
-	if redisHelper.appInfo != nil {
-		logger.FormattedLogWithAppInfo(redisHelper.appInfo, "SET LIST TO CACHE: "+string(str))
-	} else {
-		println("SET DATA TO CACHE: " + string(str))
-	}
+	redisHelper.log("SET LIST TO CACHE: " + string(str))
 
 	return nil
 }
@@ -190,7 +199,7 @@ func (redisHelper *RedisHelper[E]) SetArrayOfPointerModels(key string, models []
 // GetPointerArrayOfModels Возвращает list по ключу
 func (redisHelper *RedisHelper[E]) GetPointerArrayOfModels(key string) (*[]E, error) {
 	resultArr := make([]E, 0)
-	ctx := context.Background()
+	ctx := redisHelper.context()
 	val, err := redisHelper.redisClient.Get(ctx, key).Result()
 
 	if err != nil && !errors.Is(err, redis.Nil) {
@@ -207,11 +216,7 @@ func (redisHelper *RedisHelper[E]) GetPointerArrayOfModels(key string) (*[]E, er
 		return nil, unMarshErr
 	}
 
-	if redisHelper.appInfo != nil {
-		logger.FormattedLogWithAppInfo(redisHelper.appInfo, "GOT LIST FROM CACHE: "+val)
-	} else {
-		println("GOT LIST FROM CACHE: " + val)
-	}
+	redisHelper.log("GOT LIST FROM CACHE: " + val)
 
 	if len(resultArr) == 0 {
 		return nil, nil
@@ -233,7 +238,7 @@ func (redisHelper *RedisHelper[E]) SetPointerArrayOfModels(key string, models *[
 		return err
 	}
 
-	ctx := context.Background()
+	ctx := redisHelper.context()
 
 	rErr := redisHelper.redisClient.Set(ctx, key, str, ttl).Err()
 
@@ -241,11 +246,7 @@ func (redisHelper *RedisHelper[E]) SetPointerArrayOfModels(key string, models *[
 		return rErr
 	}
 
-	if redisHelper.appInfo != nil {
-		logger.FormattedLogWithAppInfo(redisHelper.appInfo, "SET LIST TO CACHE: "+string(str))
-	} else {
-		println("SET DATA TO CACHE: " + string(str))
-	}
+	redisHelper.log("SET LIST TO CACHE: " + string(str))
 
 	return nil
 }
@@ -0,0 +1,44 @@
+package seed
+
+import (
+	"context"
+	"flag"
+	"github.com/ZhanibekTau/go-sdk/pkg/app"
+	"strings"
+)
+
+// RegisterCommands - консольная команда сидеров для app.RunConsole, обычно в PrepareConsole:
+//
+//	db:seed [--only=users,posts]   выполнить сидеры (все или перечисленные) с зависимостями
+//
+// Если окружение раннера не задано, берется APP_ENV приложения. Без APP_ENV сидеры не выполняются
+func RegisterCommands(application *app.App, runner *Runner) {
+	application.RegisterCommand(app.Command{
+		Name:        "db:seed",
+		Description: "run seeders with dependencies [--only=name,name]",
+		Run: func(current *app.App, args []string) error {
+			flags := flag.NewFlagSet("db:seed", flag.ContinueOnError)
+			only := flags.String("only", "", "comma separated seeder names")
+
+			if err := flags.Parse(args); err != nil {
+				return err
+			}
+
+			if runner.env == "" && current.BaseConfig != nil {
+				runner.env = current.BaseConfig.AppEnv
+			}
+
+			names := make([]string, 0)
+
+			for _, name := range strings.Split(*only, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					names = append(names, name)
+				}
+			}
+
+			_, err := runner.Run(context.Background(), names...)
+
+			return err
+		},
+	})
+}
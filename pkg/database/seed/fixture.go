@@ -0,0 +1,459 @@
+package seed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Форматы файлов фикстур
+const (
+	FormatYaml = "yaml"
+	FormatJson = "json"
+)
+
+// referenceRegexp - ссылка на другую фикстуру: "$users.alice" заменяется первичным ключом записи alice из users
+var referenceRegexp = regexp.MustCompile(`^\$(\w+)\.([\w-]+)$`)
+
+// fixtureSet - фикстура -> метка записи -> колонка -> значение
+type fixtureSet map[string]map[string]map[string]any
+
+// NewLoader - загрузчик фикстур в db, модели фикстур регистрируются через Register
+func NewLoader(db *gorm.DB) *Loader {
+	return &Loader{
+		db:     db,
+		models: map[string]reflect.Type{},
+		refs:   map[string]any{},
+	}
+}
+
+// Loader - вставляет записи моделей gorm из YAML или JSON:
+//
+//	users:
+//	  alice:
+//	    email: alice@example.com
+//	posts:
+//	  hello:
+//	    title: Hello
+//	    user_id: $users.alice
+//
+// Ключ верхнего уровня - имя фикстуры из Register, дальше метки записей. Колонки - имена в БД или полей модели.
+// Записи вставляются в порядке ссылок, после вставки счетчики автоинкремента сдвигаются за максимальный ключ
+type Loader struct {
+	db     *gorm.DB
+	models map[string]reflect.Type
+	refs   map[string]any
+}
+
+// Register - модель для фикстуры name, например Register("users", &User{})
+func (l *Loader) Register(name string, model any) *Loader {
+	l.models[name] = reflect.Indirect(reflect.ValueOf(model)).Type()
+
+	return l
+}
+
+// Ref - первичный ключ загруженной записи, ref вида "users.alice"
+func (l *Loader) Ref(ref string) (any, bool) {
+	value, ok := l.refs[ref]
+
+	return value, ok
+}
+
+// LoadFile - загружает файл .yml, .yaml или .json
+func (l *Loader) LoadFile(ctx context.Context, path string) error {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return err
+	}
+
+	return l.Load(ctx, data, formatOf(path))
+}
+
+// LoadFS - загружает файлы по шаблонам (fs.Glob) как один набор, ссылки между файлами разрешаются
+func (l *Loader) LoadFS(ctx context.Context, fsys fs.FS, patterns ...string) error {
+	set := fixtureSet{}
+
+	for _, pattern := range patterns {
+		paths, err := fs.Glob(fsys, pattern)
+
+		if err != nil {
+			return err
+		}
+
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			data, rErr := fs.ReadFile(fsys, path)
+
+			if rErr != nil {
+				return rErr
+			}
+
+			fileSet, pErr := parseFixtures(data, formatOf(path))
+
+			if pErr != nil {
+				return fmt.Errorf("seed: %s: %w", path, pErr)
+			}
+
+			if mErr := set.merge(fileSet); mErr != nil {
+				return fmt.Errorf("seed: %s: %w", path, mErr)
+			}
+		}
+	}
+
+	return l.load(ctx, set)
+}
+
+// Load - загружает фикстуры из data в формате FormatYaml или FormatJson
+func (l *Loader) Load(ctx context.Context, data []byte, format string) error {
+	set, err := parseFixtures(data, format)
+
+	if err != nil {
+		return fmt.Errorf("seed: %w", err)
+	}
+
+	return l.load(ctx, set)
+}
+
+// load - вставка набора в одной транзакции
+func (l *Loader) load(ctx context.Context, set fixtureSet) error {
+	order, err := l.order(set)
+
+	if err != nil {
+		return err
+	}
+
+	inserted := map[string]bool{}
+	refs := map[string]any{}
+
+	err = l.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, key := range order {
+			name, label, _ := strings.Cut(key, ".")
+			primaryKey, iErr := l.insert(tx, name, set[name][label], refs)
+
+			if iErr != nil {
+				return fmt.Errorf("seed: fixture %s: %w", key, iErr)
+			}
+
+			refs[key] = primaryKey
+			inserted[name] = true
+		}
+
+		for name := range inserted {
+			if rErr := resetSequence(tx, reflect.New(l.models[name]).Interface()); rErr != nil {
+				return fmt.Errorf("seed: reset sequence of %s: %w", name, rErr)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// ссылки доступны только после коммита
+	for key, value := range refs {
+		l.refs[key] = value
+	}
+
+	return nil
+}
+
+// insert - создает запись модели, возвращает ее первичный ключ
+func (l *Loader) insert(tx *gorm.DB, name string, columns map[string]any, refs map[string]any) (any, error) {
+	model := reflect.New(l.models[name])
+	stmt := &gorm.Statement{DB: tx}
+
+	if err := stmt.Parse(model.Interface()); err != nil {
+		return nil, err
+	}
+
+	explicitIdentity := false
+
+	for column, value := range columns {
+		field := stmt.Schema.LookUpField(column)
+
+		if field == nil {
+			return nil, fmt.Errorf("unknown column %s", column)
+		}
+
+		if field == stmt.Schema.PrioritizedPrimaryField && field.AutoIncrement {
+			explicitIdentity = true
+		}
+
+		if key, isRef := l.reference(value); isRef {
+			resolved, ok := refs[key]
+
+			if !ok {
+				resolved, ok = l.refs[key]
+			}
+
+			if !ok {
+				return nil, fmt.Errorf("unknown reference %s", key)
+			}
+
+			value = resolved
+		}
+
+		if err := field.Set(tx.Statement.Context, model.Elem(), value); err != nil {
+			return nil, err
+		}
+	}
+
+	// SQL Server не принимает явное значение IDENTITY колонки без SET IDENTITY_INSERT
+	if explicitIdentity && tx.Dialector.Name() == "sqlserver" {
+		if err := identityInsert(tx, stmt, true); err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			_ = identityInsert(tx, stmt, false)
+		}()
+	}
+
+	if err := tx.Create(model.Interface()).Error; err != nil {
+		return nil, err
+	}
+
+	if stmt.Schema.PrioritizedPrimaryField == nil {
+		return nil, nil
+	}
+
+	primaryKey, _ := stmt.Schema.PrioritizedPrimaryField.ValueOf(tx.Statement.Context, model.Elem())
+
+	return primaryKey, nil
+}
+
+// order - ключи записей "фикстура.метка", записи со ссылками после тех, на кого ссылаются
+func (l *Loader) order(set fixtureSet) ([]string, error) {
+	keys := make([]string, 0)
+
+	for name, rows := range set {
+		if _, ok := l.models[name]; !ok {
+			return nil, fmt.Errorf("seed: fixture %s is not registered", name)
+		}
+
+		for label := range rows {
+			keys = append(keys, name+"."+label)
+		}
+	}
+
+	sort.Strings(keys)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	state := map[string]int{}
+	order := make([]string, 0, len(keys))
+
+	var visit func(key string) error
+
+	visit = func(key string) error {
+		switch state[key] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("seed: reference cycle at %s", key)
+		}
+
+		state[key] = visiting
+		name, label, _ := strings.Cut(key, ".")
+		columns := set[name][label]
+		dependencies := make([]string, 0)
+
+		for _, value := range columns {
+			if ref, isRef := l.reference(value); isRef {
+				dependencies = append(dependencies, ref)
+			}
+		}
+
+		sort.Strings(dependencies)
+
+		for _, dependency := range dependencies {
+			refName, refLabel, _ := strings.Cut(dependency, ".")
+
+			// ссылка на запись из ранее загруженного набора
+			if _, inSet := set[refName][refLabel]; !inSet {
+				continue
+			}
+
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+
+		state[key] = visited
+		order = append(order, key)
+
+		return nil
+	}
+
+	for _, key := range keys {
+		if err := visit(key); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// reference - ссылка "$фикстура.метка" на зарегистрированную фикстуру, иначе строка - обычное значение
+func (l *Loader) reference(value any) (string, bool) {
+	text, ok := value.(string)
+
+	if !ok {
+		return "", false
+	}
+
+	match := referenceRegexp.FindStringSubmatch(text)
+
+	if match == nil {
+		return "", false
+	}
+
+	if _, registered := l.models[match[1]]; !registered {
+		return "", false
+	}
+
+	return match[1] + "." + match[2], true
+}
+
+// identityInsert - включает или выключает вставку явных значений IDENTITY колонки таблицы модели в SQL Server.
+// Действует на соединение, поэтому вызывается внутри транзакции загрузки
+func identityInsert(tx *gorm.DB, stmt *gorm.Statement, on bool) error {
+	state := "OFF"
+
+	if on {
+		state = "ON"
+	}
+
+	table := stmt.Schema.Table
+
+	return tx.Exec(
+		fmt.Sprintf("IF OBJECTPROPERTY(OBJECT_ID(?), 'TableHasIdentity') = 1 SET IDENTITY_INSERT %s %s", stmt.Quote(table), state),
+		table,
+	).Error
+}
+
+// resetSequence - сдвигает счетчик автоинкремента за максимальный ключ, если ключи в фикстурах заданы явно.
+// MySQL и Sqlite делают это сами
+func resetSequence(tx *gorm.DB, model any) error {
+	stmt := &gorm.Statement{DB: tx}
+
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+
+	field := stmt.Schema.PrioritizedPrimaryField
+
+	if field == nil || !field.AutoIncrement {
+		return nil
+	}
+
+	table := stmt.Schema.Table
+
+	switch tx.Dialector.Name() {
+	case "postgres":
+		return tx.Exec(
+			fmt.Sprintf("SELECT setval(pg_get_serial_sequence(?, ?), COALESCE(MAX(%s), 0) + 1, false) FROM %s",
+				stmt.Quote(field.DBName), stmt.Quote(table)),
+			table, field.DBName,
+		).Error
+	case "sqlserver":
+		return tx.Exec(
+			fmt.Sprintf("IF OBJECTPROPERTY(OBJECT_ID(?), 'TableHasIdentity') = 1 DBCC CHECKIDENT (%s, RESEED)", stmt.Quote(table)),
+			table,
+		).Error
+	}
+
+	return nil
+}
+
+func parseFixtures(data []byte, format string) (fixtureSet, error) {
+	set := fixtureSet{}
+
+	switch format {
+	case FormatYaml:
+		if err := yaml.Unmarshal(data, &set); err != nil {
+			return nil, err
+		}
+	case FormatJson:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+
+		if err := decoder.Decode(&set); err != nil {
+			return nil, err
+		}
+
+		for _, rows := range set {
+			for _, columns := range rows {
+				for column, value := range columns {
+					columns[column] = jsonNumber(value)
+				}
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown fixture format %q", format)
+	}
+
+	return set, nil
+}
+
+// jsonNumber - json.Number в int64 или float64, иначе gorm не запишет его в числовое поле
+func jsonNumber(value any) any {
+	number, ok := value.(json.Number)
+
+	if !ok {
+		return value
+	}
+
+	if integer, err := number.Int64(); err == nil {
+		return integer
+	}
+
+	if float, err := number.Float64(); err == nil {
+		return float
+	}
+
+	return number.String()
+}
+
+func (s fixtureSet) merge(other fixtureSet) error {
+	for name, rows := range other {
+		if s[name] == nil {
+			s[name] = map[string]map[string]any{}
+		}
+
+		for label, columns := range rows {
+			if _, exists := s[name][label]; exists {
+				return fmt.Errorf("duplicate fixture %s.%s", name, label)
+			}
+
+			s[name][label] = columns
+		}
+	}
+
+	return nil
+}
+
+func formatOf(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return FormatJson
+	}
+
+	return FormatYaml
+}
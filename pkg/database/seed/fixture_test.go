@@ -0,0 +1,116 @@
+package seed
+
+import (
+	"context"
+	"github.com/ZhanibekTau/go-sdk/pkg/database/dbtest"
+	"gorm.io/gorm"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+type fixtureUser struct {
+	ID    int `gorm:"primaryKey"`
+	Email string
+}
+
+type fixturePost struct {
+	ID       int `gorm:"primaryKey"`
+	Title    string
+	AuthorId int
+}
+
+// fixtureLoader - загрузчик с моделями users и posts
+func fixtureLoader(db *gorm.DB) *Loader {
+	return NewLoader(db).Register("users", &fixtureUser{}).Register("posts", &fixturePost{})
+}
+
+func TestLoaderResolvesReferences(t *testing.T) {
+	db := dbtest.NewTestDB(t, &fixtureUser{}, &fixturePost{})
+	loader := fixtureLoader(db)
+
+	// посты объявлены раньше пользователей, порядок вставки берется из ссылок
+	err := loader.Load(context.Background(), []byte(`
+posts:
+  hello:
+    title: Hello
+    author_id: $users.bob
+users:
+  alice:
+    email: alice@example.com
+  bob:
+    Email: bob@example.com
+`), FormatYaml)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bobId, ok := loader.Ref("users.bob")
+
+	if !ok {
+		t.Fatal("no reference users.bob")
+	}
+
+	var post fixturePost
+
+	if err = db.First(&post, "title = ?", "Hello").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if post.AuthorId != bobId {
+		t.Fatalf("author %d, want %v", post.AuthorId, bobId)
+	}
+}
+
+func TestLoaderLoadFSAcrossFiles(t *testing.T) {
+	db := dbtest.NewTestDB(t, &fixtureUser{}, &fixturePost{})
+	loader := fixtureLoader(db)
+	fsys := fstest.MapFS{
+		"fixtures/1_users.json": {Data: []byte(`{"users": {"alice": {"id": 10, "email": "alice@example.com"}}}`)},
+		"fixtures/2_posts.yml":  {Data: []byte("posts:\n  first:\n    title: First\n    author_id: $users.alice\n")},
+	}
+
+	if err := loader.LoadFS(context.Background(), fsys, "fixtures/*"); err != nil {
+		t.Fatal(err)
+	}
+
+	var post fixturePost
+
+	if err := db.First(&post).Error; err != nil || post.AuthorId != 10 {
+		t.Fatalf("post %+v, %v", post, err)
+	}
+
+	// после явных ключей автоинкремент продолжается за максимальным
+	user := fixtureUser{Email: "new@example.com"}
+
+	if err := db.Create(&user).Error; err != nil || user.ID <= 10 {
+		t.Fatalf("new user id %d, %v", user.ID, err)
+	}
+}
+
+func TestLoaderErrors(t *testing.T) {
+	cases := map[string]string{
+		"is not registered": "comments:\n  first:\n    body: text\n",
+		"reference cycle":   "posts:\n  a:\n    id: $posts.b\n  b:\n    id: $posts.a\n",
+		"unknown column":    "posts:\n  hello:\n    body: text\n",
+		"unknown reference": "posts:\n  hello:\n    author_id: $users.nobody\n",
+	}
+
+	for want, data := range cases {
+		db := dbtest.NewTestDB(t, &fixtureUser{}, &fixturePost{})
+		loader := fixtureLoader(db)
+		err := loader.Load(context.Background(), []byte("users:\n  bob:\n    email: bob@example.com\n"+data), FormatYaml)
+
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("got %v, want %q", err, want)
+		}
+
+		// набор вставляется в одной транзакции
+		var count int64
+
+		if err = db.Model(&fixtureUser{}).Count(&count).Error; err != nil || count != 0 {
+			t.Fatalf("%s: users after failed load %d, %v", want, count, err)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZhanibekTau/go-sdk/pkg/config"
+	"gorm.io/gorm"
+	"io"
+	"os"
+	"slices"
+	"sort"
+)
+
+// EnvProd - окружение, в котором сидеры не выполняются никогда
+const EnvProd = "prod"
+
+// ErrProdEnvironment - попытка запустить сидеры в prod
+var ErrProdEnvironment = errors.New("seed: seeders are disabled in prod environment")
+
+// ErrEnvironmentNotSet - окружение раннера неизвестно, поэтому сидеры не выполняются: это может быть prod
+var ErrEnvironmentNotSet = errors.New("seed: environment is not set")
+
+// Seeder - наполнение БД демо или тестовыми данными
+type Seeder struct {
+	Name string
+	// DependsOn - сидеры, которые должны выполниться раньше, запускаются автоматически
+	DependsOn []string
+	// Envs - окружения (APP_ENV), где выполняется сидер, пусто - окружения разработки (config.DevelopmentEnvs).
+	// В prod не выполняется никогда. Сидеры, зависящие от пропущенного, тоже пропускаются
+	Envs []string
+	// Run - получает транзакцию, ошибка откатывает данные этого сидера
+	Run func(ctx context.Context, tx *gorm.DB) error
+}
+
+// allowed - выполняется ли сидер в окружении env: явно указанные Envs или окружения разработки
+func (s *Seeder) allowed(env string) bool {
+	if len(s.Envs) == 0 {
+		return config.IsDevelopmentEnv(env)
+	}
+
+	return slices.Contains(s.Envs, env)
+}
+
+// NewRunner - запуск сидеров для db в окружении env (обычно BaseConfig.AppEnv)
+func NewRunner(db *gorm.DB, env string) *Runner {
+	return &Runner{
+		db:      db,
+		env:     env,
+		seeders: map[string]*Seeder{},
+		output:  os.Stdout,
+	}
+}
+
+// Runner - реестр сидеров и их запуск в порядке зависимостей
+type Runner struct {
+	db      *gorm.DB
+	env     string
+	seeders map[string]*Seeder
+	output  io.Writer
+}
+
+// SetOutput - куда писать ход выполнения
+func (r *Runner) SetOutput(output io.Writer) *Runner {
+	r.output = output
+
+	return r
+}
+
+// Register - добавляет сидеры, имя должно быть уникальным
+func (r *Runner) Register(seeders ...Seeder) error {
+	for i := range seeders {
+		seeder := seeders[i]
+
+		if seeder.Name == "" || seeder.Run == nil {
+			return fmt.Errorf("seed: seeder %q must have name and run", seeder.Name)
+		}
+
+		if _, exists := r.seeders[seeder.Name]; exists {
+			return fmt.Errorf("seed: duplicate seeder %s", seeder.Name)
+		}
+
+		r.seeders[seeder.Name] = &seeder
+	}
+
+	return nil
+}
+
+// Run - выполняет сидеры names с зависимостями, без names - все. Сидеры не для текущего окружения и зависящие
+// от них пропускаются. Без окружения и в prod сидеры не выполняются. Возвращает выполненные сидеры по порядку
+func (r *Runner) Run(ctx context.Context, names ...string) ([]string, error) {
+	if r.env == "" {
+		return nil, ErrEnvironmentNotSet
+	}
+
+	if r.env == EnvProd {
+		return nil, ErrProdEnvironment
+	}
+
+	if len(names) == 0 {
+		for name := range r.seeders {
+			names = append(names, name)
+		}
+	}
+
+	// порядок независимых сидеров не должен зависеть от обхода map
+	sort.Strings(names)
+
+	order, err := r.resolve(names)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(order))
+	skipped := map[string]bool{}
+
+	for _, seeder := range order {
+		if !seeder.allowed(r.env) {
+			skipped[seeder.Name] = true
+			_, _ = fmt.Fprintf(r.output, "skipped: %s (not for %s)\n", seeder.Name, r.env)
+
+			continue
+		}
+
+		// зависимости идут раньше в order, поэтому пропуск уже известен
+		if index := slices.IndexFunc(seeder.DependsOn, func(name string) bool { return skipped[name] }); index >= 0 {
+			skipped[seeder.Name] = true
+			_, _ = fmt.Fprintf(r.output, "skipped: %s (dependency %s skipped)\n", seeder.Name, seeder.DependsOn[index])
+
+			continue
+		}
+
+		_, _ = fmt.Fprintf(r.output, "seeding: %s\n", seeder.Name)
+
+		err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return seeder.Run(ctx, tx)
+		})
+
+		if err != nil {
+			return result, fmt.Errorf("seed: %s: %w", seeder.Name, err)
+		}
+
+		result = append(result, seeder.Name)
+	}
+
+	return result, nil
+}
+
+// resolve - сидеры с зависимостями, зависимости раньше зависящих
+func (r *Runner) resolve(names []string) ([]*Seeder, error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	state := map[string]int{}
+	order := make([]*Seeder, 0, len(names))
+
+	var visit func(name string, path []string) error
+
+	visit = func(name string, path []string) error {
+		seeder, ok := r.seeders[name]
+
+		if !ok {
+			return fmt.Errorf("seed: unknown seeder %s", name)
+		}
+
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("seed: dependency cycle %v", append(path, name))
+		}
+
+		state[name] = visiting
+
+		for _, dependency := range seeder.DependsOn {
+			if err := visit(dependency, append(path, name)); err != nil {
+				return err
+			}
+		}
+
+		state[name] = visited
+		order = append(order, seeder)
+
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
@@ -0,0 +1,100 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ZhanibekTau/go-sdk/pkg/database/dbtest"
+	"gorm.io/gorm"
+	"io"
+	"testing"
+)
+
+// seedRunner - раннер с сидерами seeders в окружении env
+func seedRunner(t *testing.T, env string, seeders ...Seeder) *Runner {
+	t.Helper()
+
+	runner := NewRunner(dbtest.NewTestDB(t, &fixtureUser{}), env).SetOutput(io.Discard)
+
+	if err := runner.Register(seeders...); err != nil {
+		t.Fatal(err)
+	}
+
+	return runner
+}
+
+func noop(context.Context, *gorm.DB) error {
+	return nil
+}
+
+func TestRunnerRunsDependenciesFirst(t *testing.T) {
+	runner := seedRunner(t, "local",
+		Seeder{Name: "posts", DependsOn: []string{"users"}, Run: noop},
+		Seeder{Name: "users", Run: noop},
+		Seeder{Name: "tags", Run: noop},
+	)
+
+	result, err := runner.Run(context.Background(), "posts")
+
+	if err != nil || fmt.Sprint(result) != "[users posts]" {
+		t.Fatalf("got %v, %v", result, err)
+	}
+}
+
+func TestRunnerSkipsDependentsOfSkipped(t *testing.T) {
+	runner := seedRunner(t, "local",
+		Seeder{Name: "users", Envs: []string{"dev"}, Run: noop},
+		Seeder{Name: "posts", DependsOn: []string{"users"}, Run: noop},
+		Seeder{Name: "comments", DependsOn: []string{"posts"}, Run: noop},
+		Seeder{Name: "tags", Run: noop},
+	)
+
+	result, err := runner.Run(context.Background())
+
+	if err != nil || fmt.Sprint(result) != "[tags]" {
+		t.Fatalf("got %v, %v", result, err)
+	}
+}
+
+func TestRunnerRunsSeedersWithoutEnvsOnlyInDevelopment(t *testing.T) {
+	runner := seedRunner(t, "stage",
+		Seeder{Name: "demo", Run: noop},
+		Seeder{Name: "catalog", Envs: []string{"stage"}, Run: noop},
+	)
+
+	result, err := runner.Run(context.Background())
+
+	if err != nil || fmt.Sprint(result) != "[catalog]" {
+		t.Fatalf("got %v, %v", result, err)
+	}
+}
+
+func TestRunnerRefusesProdAndEmptyEnv(t *testing.T) {
+	for env, want := range map[string]error{"": ErrEnvironmentNotSet, EnvProd: ErrProdEnvironment} {
+		runner := seedRunner(t, env, Seeder{Name: "users", Run: noop})
+
+		if _, err := runner.Run(context.Background()); !errors.Is(err, want) {
+			t.Fatalf("env %q: got %v, want %v", env, err, want)
+		}
+	}
+}
+
+func TestRunnerRollsBackFailedSeeder(t *testing.T) {
+	runner := seedRunner(t, "local", Seeder{Name: "users", Run: func(ctx context.Context, tx *gorm.DB) error {
+		if err := tx.Create(&fixtureUser{Email: "alice@example.com"}).Error; err != nil {
+			return err
+		}
+
+		return errors.New("fail")
+	}})
+
+	if _, err := runner.Run(context.Background()); err == nil {
+		t.Fatal("error is not returned")
+	}
+
+	var count int64
+
+	if err := runner.db.Model(&fixtureUser{}).Count(&count).Error; err != nil || count != 0 {
+		t.Fatalf("users after failed seeder %d, %v", count, err)
+	}
+}
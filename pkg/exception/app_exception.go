@@ -1,37 +1,179 @@
 package exception
 
 import (
+	"fmt"
 	"github.com/ZhanibekTau/go-sdk/pkg/constants"
 	"github.com/ZhanibekTau/go-sdk/pkg/validation"
 	"github.com/go-errors/errors"
 	"github.com/gookit/validate"
 	"net/http"
+	"runtime"
+	"sync/atomic"
 )
 
-// AppException Модель данных для описания ошибки
+// stackDepth - максимальная глубина сохраняемого стека
+const stackDepth = 32
+
+var captureStack atomic.Bool
+
+func init() {
+	captureStack.Store(true)
+}
+
+// SetCaptureStack - сохранять ли стек вызова при создании AppException, по умолчанию включено
+func SetCaptureStack(enabled bool) {
+	captureStack.Store(enabled)
+}
+
+// AppException Модель данных для описания ошибки, реализует error: работают errors.Is и errors.As
 type AppException struct {
-	Code        int
-	Error       error
+	Code int
+	// Err - исходная ошибка, ее возвращает Unwrap
+	Err         error
 	Context     map[string]any
 	ServiceCode int
+	// ErrorType - тип ошибки из каталога (см. Define), пусто - тип по статусу
+	ErrorType string
+	// stack - стек вызова на момент создания
+	stack []uintptr
+}
+
+// Error - сообщение исходной ошибки, без нее - текст статуса
+func (a *AppException) Error() string {
+	if a.Err != nil {
+		return a.Err.Error()
+	}
+
+	return http.StatusText(a.Code)
+}
+
+func (a *AppException) Unwrap() error {
+	return a.Err
+}
+
+// Is - ошибки каталога сравниваются по service code: errors.Is(err, ErrOrderNotFound) и errors.Is(err, ErrOrderNotFound.New(...))
+func (a *AppException) Is(target error) bool {
+	switch typed := target.(type) {
+	case *Definition:
+		return a.ServiceCode != 0 && a.ServiceCode == typed.ServiceCode
+	case *AppException:
+		return a.ServiceCode != 0 && a.ServiceCode == typed.ServiceCode
+	}
+
+	return false
 }
 
 func (a *AppException) GetErrorType() string {
+	if a.ErrorType != "" {
+		return a.ErrorType
+	}
+
 	return constants.GetErrorTypeByStatusCode(a.Code)
 }
 
+// WithContext - добавляет значение в контекст ошибки
+func (a *AppException) WithContext(key string, value any) *AppException {
+	if a.Context == nil {
+		a.Context = make(map[string]any)
+	}
+
+	a.Context[key] = value
+
+	return a
+}
+
+// StackFrames - стек исходной ошибки go-errors, если он есть, иначе стек создания AppException.
+// По этому методу стек находит и сентри
+func (a *AppException) StackFrames() []errors.StackFrame {
+	var goErr *errors.Error
+
+	if errors.As(a.Err, &goErr) {
+		return goErr.StackFrames()
+	}
+
+	frames := make([]errors.StackFrame, len(a.stack))
+
+	for i, pc := range a.stack {
+		frames[i] = errors.NewStackFrame(pc)
+	}
+
+	return frames
+}
+
+// Stack - стек в читаемом виде для логов
+func (a *AppException) Stack() string {
+	result := ""
+
+	for _, frame := range a.StackFrames() {
+		result += frame.String()
+	}
+
+	return result
+}
+
+// newAppException - skip - количество фреймов над вызывающим, которые не попадут в стек
+func newAppException(code int, err error, context map[string]any, skip int) *AppException {
+	appException := &AppException{Code: code, Err: err, Context: context}
+
+	if captureStack.Load() {
+		stack := make([]uintptr, stackDepth)
+		length := runtime.Callers(skip+2, stack)
+		appException.stack = stack[:length]
+	}
+
+	return appException
+}
+
 func NewAppException(code int, err error, context map[string]any) *AppException {
-	return &AppException{code, err, context, 0}
+	return newAppException(code, err, context, 1)
 }
 
 func NewInternalServerAppException(err error, context map[string]any) *AppException {
-	return &AppException{http.StatusInternalServerError, err, context, 0}
+	return newAppException(http.StatusInternalServerError, err, context, 1)
 }
 
 func NewValidationAppException(context map[string]any) *AppException {
-	return &AppException{http.StatusUnprocessableEntity, errors.New("VALIDATION ERROR"), context, 0}
+	return newAppException(http.StatusUnprocessableEntity, errors.New("VALIDATION ERROR"), context, 1)
 }
 
 func NewValidationAppExceptionFromValidationErrors(validationErrors validate.Errors) *AppException {
-	return NewValidationAppException(validation.ValidationErrorsAsMap(validationErrors))
+	return newAppException(http.StatusUnprocessableEntity, errors.New("VALIDATION ERROR"), validation.ValidationErrorsAsMap(validationErrors), 1)
+}
+
+// Wrap - оборачивает ошибку в AppException со статусом code. Если в цепочке уже есть AppException,
+// возвращается он с добавленным контекстом, статус не меняется
+func Wrap(err error, code int, context map[string]any) *AppException {
+	if err == nil {
+		return nil
+	}
+
+	if appException, ok := As(err); ok {
+		for key, value := range context {
+			appException.WithContext(key, value)
+		}
+
+		return appException
+	}
+
+	return newAppException(code, err, context, 1)
+}
+
+// Wrapf - оборачивает ошибку с поясняющим сообщением: "сообщение: исходная ошибка", исходная ошибка доступна через errors.Is/As
+func Wrapf(err error, code int, format string, args ...any) *AppException {
+	if err == nil {
+		return nil
+	}
+
+	return newAppException(code, fmt.Errorf(format+": %w", append(args, err)...), nil, 1)
+}
+
+// As - AppException из цепочки ошибок
+func As(err error) (*AppException, bool) {
+	var appException *AppException
+
+	if errors.As(err, &appException) {
+		return appException, true
+	}
+
+	return nil, false
 }
@@ -0,0 +1,85 @@
+package exception
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ZhanibekTau/go-sdk/pkg/constants"
+	"io"
+	"strings"
+)
+
+const CatalogueFormatJson = "json"
+const CatalogueFormatMarkdown = "markdown"
+
+// WriteCatalogue - выгружает каталог ошибок в формате CatalogueFormatJson или CatalogueFormatMarkdown,
+// используется консольной командой exception:catalogue (см. app.RunConsole)
+func WriteCatalogue(w io.Writer, format string) error {
+	switch format {
+	case CatalogueFormatJson:
+		return WriteCatalogueJson(w)
+	case CatalogueFormatMarkdown:
+		return WriteCatalogueMarkdown(w)
+	}
+
+	return fmt.Errorf("exception: unknown catalogue format %q", format)
+}
+
+// catalogueItem - ошибка каталога при выгрузке
+type catalogueItem struct {
+	Status      int               `json:"status"`
+	ServiceCode int               `json:"service_code"`
+	ErrorType   string            `json:"error"`
+	Messages    map[string]string `json:"messages"`
+}
+
+// WriteCatalogueJson - выгружает каталог ошибок в JSON для фронтенда
+func WriteCatalogueJson(w io.Writer) error {
+	items := make([]catalogueItem, 0)
+
+	for _, definition := range Definitions() {
+		items = append(items, catalogueItem{
+			Status:      definition.Status,
+			ServiceCode: definition.ServiceCode,
+			ErrorType:   definition.ErrorType,
+			Messages:    definition.Messages,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+
+	return encoder.Encode(items)
+}
+
+// WriteCatalogueMarkdown - выгружает каталог ошибок таблицей Markdown
+func WriteCatalogueMarkdown(w io.Writer) error {
+	languages := []string{constants.LangCodeRu, constants.LangCodeKZ, constants.LangCodeEN}
+	builder := strings.Builder{}
+
+	builder.WriteString("| service_code | status | error |")
+
+	for _, language := range languages {
+		builder.WriteString(" " + language + " |")
+	}
+
+	builder.WriteString("\n|---|---|---|" + strings.Repeat("---|", len(languages)) + "\n")
+
+	for _, definition := range Definitions() {
+		builder.WriteString(fmt.Sprintf("| %d | %d | %s |", definition.ServiceCode, definition.Status, definition.ErrorType))
+
+		for _, language := range languages {
+			builder.WriteString(" " + escapeMarkdown(definition.Messages[language]) + " |")
+		}
+
+		builder.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, builder.String())
+
+	return err
+}
+
+func escapeMarkdown(value string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(value)
+}
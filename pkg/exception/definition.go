@@ -0,0 +1,125 @@
+package exception
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ZhanibekTau/go-sdk/pkg/config"
+	"github.com/ZhanibekTau/go-sdk/pkg/constants"
+	"sort"
+	"sync"
+)
+
+// Messages - сообщение ошибки по коду языка (constants.LangCodeRu, constants.LangCodeKZ, constants.LangCodeEN)
+type Messages map[string]string
+
+// Definition - описание доменной ошибки в каталоге
+type Definition struct {
+	Status      int
+	ServiceCode int
+	ErrorType   string
+	Messages    Messages
+}
+
+var (
+	definitionsMu sync.RWMutex
+	definitions   = make(map[int]*Definition)
+)
+
+// Define - объявляет доменную ошибку и добавляет ее в каталог, объявляется один раз на уровне пакета:
+//
+//	var ErrOrderNotFound = exception.Define(404, 10021, "order_not_found", exception.Messages{"ru": "Заказ не найден", "en": "Order not found"})
+//
+// Повторный service code - ошибка программиста, поэтому паника при старте приложения
+func Define(status int, serviceCode int, errorType string, messages Messages) *Definition {
+	definitionsMu.Lock()
+	defer definitionsMu.Unlock()
+
+	if existing, exists := definitions[serviceCode]; exists {
+		panic(fmt.Sprintf("exception: service code %d already defined as %s", serviceCode, existing.ErrorType))
+	}
+
+	definition := &Definition{
+		Status:      status,
+		ServiceCode: serviceCode,
+		ErrorType:   errorType,
+		Messages:    messages,
+	}
+	definitions[serviceCode] = definition
+
+	return definition
+}
+
+// Definitions - каталог ошибок, отсортированный по service code
+func Definitions() []*Definition {
+	definitionsMu.RLock()
+	defer definitionsMu.RUnlock()
+
+	result := make([]*Definition, 0, len(definitions))
+
+	for _, definition := range definitions {
+		result = append(result, definition)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ServiceCode < result[j].ServiceCode
+	})
+
+	return result
+}
+
+// GetDefinition - ошибка каталога по service code
+func GetDefinition(serviceCode int) (*Definition, bool) {
+	definitionsMu.RLock()
+	defer definitionsMu.RUnlock()
+
+	definition, exists := definitions[serviceCode]
+
+	return definition, exists
+}
+
+// New - AppException с сообщением на языке запроса из AppInfo
+func (d *Definition) New(appInfo *config.AppInfo, context map[string]any) *AppException {
+	languageCode := constants.LangCodeRu
+
+	if appInfo != nil && appInfo.LanguageCode != "" {
+		languageCode = appInfo.LanguageCode
+	}
+
+	return d.newAppException(languageCode, context)
+}
+
+// NewWithLanguage - AppException с сообщением на указанном языке
+func (d *Definition) NewWithLanguage(languageCode string, context map[string]any) *AppException {
+	return d.newAppException(languageCode, context)
+}
+
+func (d *Definition) newAppException(languageCode string, context map[string]any) *AppException {
+	appException := newAppException(d.Status, errors.New(d.Message(languageCode)), context, 2)
+	appException.ServiceCode = d.ServiceCode
+	appException.ErrorType = d.ErrorType
+
+	return appException
+}
+
+// Message - сообщение на языке, если перевода нет - на русском, затем на английском, затем тип ошибки
+func (d *Definition) Message(languageCode string) string {
+	for _, code := range []string{languageCode, constants.LangCodeRu, constants.LangCodeEN} {
+		if message, exists := d.Messages[code]; exists && message != "" {
+			return message
+		}
+	}
+
+	return d.ErrorType
+}
+
+// Error - тип ошибки, Definition реализует error, чтобы быть целью errors.Is
+func (d *Definition) Error() string {
+	return d.ErrorType
+}
+
+// Is - относится ли ошибка к этой ошибке каталога: AppException с тем же service code в цепочке err
+func (d *Definition) Is(err error) bool {
+	appException, ok := As(err)
+
+	return ok && appException != nil && appException.ServiceCode == d.ServiceCode && appException.ServiceCode != 0
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/ZhanibekTau/go-sdk/pkg/exception"
 	"github.com/ZhanibekTau/go-sdk/pkg/gin/validation"
 	"github.com/ZhanibekTau/go-sdk/pkg/http/helpers"
+	"github.com/ZhanibekTau/go-sdk/pkg/logger"
+	"github.com/ZhanibekTau/go-sdk/pkg/metrics"
 	"github.com/getsentry/sentry-go"
 	sentrygin "github.com/getsentry/sentry-go/gin"
 	"github.com/gin-gonic/gin"
@@ -18,13 +20,12 @@ import (
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	timeout "github.com/vearne/gin-timeout"
-	ginprometheus "github.com/zsais/go-gin-prometheus"
 	"net/http"
 	"strconv"
 	"time"
 )
 
-// InitRouter Базовая инициализация gin
+// InitRouter Базовая инициализация gin. Настройки метрик читаются здесь, если сервис не вызвал metrics.InitMetrics раньше
 func InitRouter(baseConfig *config.BaseConfig) *gin.Engine {
 	if baseConfig.AppEnv == "prod" {
 		gin.SetMode(gin.ReleaseMode)
@@ -47,27 +48,87 @@ func InitRouter(baseConfig *config.BaseConfig) *gin.Engine {
 		c.JSON(http.StatusNotFound, gin.H{"code": "PAGE_NOT_FOUND", "message": "404 page not found"})
 	})
 	router.HandleMethodNotAllowed = true
-	p := ginprometheus.NewPrometheus("ginHelpers")
-	p.Use(router)
+
+	if err := metrics.InitMetrics(); err != nil {
+		logger.Error("metrics config error: %v", err)
+	}
+
+	router.Use(metrics.HttpMiddleware())
+	router.GET(metrics.Path(), metrics.Handler())
 	router.Use(sentrygin.New(sentrygin.Options{}))
 	//router.Use(gin.Logger())
 	router.Use(timeout.Timeout(timeout.WithTimeout(time.Duration(baseConfig.HandlerTimeout) * time.Second)))
-	router.Use(gin.CustomRecovery(ErrorHandler))
+	router.Use(gin.CustomRecovery(ErrorHandlerWithConfig(baseConfig)))
 
 	return router
 }
 
-// ErrorHandler Обработчик ошибок gin
+// ErrorHandler Обработчик паники gin без конфига: стек отдается клиенту только в окружениях разработки
+// (config.DevelopmentEnvs), окружение берется из AppInfo. Без RequestMiddleware окружение неизвестно и стек не отдается
 func ErrorHandler(c *gin.Context, err any) {
-	goErr := errors.Wrap(err, 2)
-	details := make([]string, 0)
+	showStack := false
+
+	if value, exists := c.Get("app_info"); exists {
+		showStack = config.IsDevelopmentEnv(value.(*config.AppInfo).AppEnv)
+	}
+
+	handlePanic(c, err, showStack)
+}
+
+// ErrorHandlerWithConfig Обработчик паники gin: стек уходит в сентри и лог, клиент получает стандартный ответ с ошибкой.
+// Стек в details отдается только при DEBUG или в окружениях разработки (config.DevelopmentEnvs)
+func ErrorHandlerWithConfig(baseConfig *config.BaseConfig) gin.RecoveryFunc {
+	showStack := baseConfig != nil && (baseConfig.Debug || config.IsDevelopmentEnv(baseConfig.AppEnv))
+
+	return func(c *gin.Context, err any) {
+		handlePanic(c, err, showStack)
+	}
+}
+
+func handlePanic(c *gin.Context, err any, showStack bool) {
+	goErr := errors.Wrap(err, 3)
+	appInfo := GetAppInfo(c)
+	stack := make([]string, 0)
 
 	for _, frame := range goErr.StackFrames() {
-		details = append(details, frame.String())
+		stack = append(stack, frame.String())
+	}
+
+	hub := sentrygin.GetHubFromContext(c)
+
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+
+	hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetTags(map[string]string{
+			"request_id":     appInfo.RequestId,
+			"service_name":   appInfo.ServiceName,
+			"app_env":        appInfo.AppEnv,
+			"request_method": appInfo.RequestMethod,
+			"request_url":    appInfo.RequestUrl,
+			"language_code":  appInfo.LanguageCode,
+			"city_id":        strconv.Itoa(appInfo.CityId),
+			"user_id":        strconv.Itoa(appInfo.UserId),
+			"client_id":      appInfo.ClientId,
+		})
+		helpers.ApplyExceptionContexts(c, scope)
+		hub.CaptureException(goErr)
+	})
+
+	logger.FormattedError(appInfo.ServiceName, appInfo.RequestMethod, appInfo.RequestUrl, http.StatusInternalServerError, appInfo.RequestId,
+		"panic: "+goErr.Error()+"\n"+string(goErr.Stack()))
+
+	details := map[string]any{"request_id": appInfo.RequestId}
+
+	if showStack {
+		details["panic"] = goErr.Error()
+		details["stack"] = stack
 	}
 
-	sentry.CaptureException(goErr)
-	c.JSON(http.StatusInternalServerError, gin.H{"message": goErr.Error(), "details": details, "success": false, "service_code": 0})
+	// паника уже отправлена в сентри со стеком, повторное событие от FormattedResponse не нужно
+	helpers.MarkExceptionCaptured(c)
+	helpers.FormattedAppExceptionResponse(c, exception.NewInternalServerAppException(errors.New("internal server error"), details))
 }
 
 func Error(c *gin.Context, exception *exception.AppException) {
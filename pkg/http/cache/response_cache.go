@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+// addTagScript - добавляет ключ ответа в множество тега, множество живет не меньше самого долгого ответа в нем
+var addTagScript = redis.NewScript(`
+redis.call('SADD', KEYS[1], ARGV[1])
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < tonumber(ARGV[2]) then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 1
+`)
+
+// CachedResponse - сохраненный ответ
+type CachedResponse struct {
+	StatusCode   int       `json:"status_code"`
+	ContentType  string    `json:"content_type"`
+	Body         []byte    `json:"body"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// NewResponseCache - кэш HTTP ответов в редисе. Используется CacheMiddleware и сервисным кодом для инвалидации по тегам
+func NewResponseCache(redisClient *redis.Client) *ResponseCache {
+	return &ResponseCache{
+		redisClient: redisClient,
+		prefix:      "http_cache:",
+	}
+}
+
+// ResponseCache - кэш HTTP ответов в редисе
+type ResponseCache struct {
+	redisClient *redis.Client
+	prefix      string
+}
+
+// SetPrefix - префикс ключей в редисе
+func (rc *ResponseCache) SetPrefix(prefix string) *ResponseCache {
+	rc.prefix = prefix
+
+	return rc
+}
+
+// Get - возвращает ответ по ключу, nil если ответа нет в кэше
+func (rc *ResponseCache) Get(ctx context.Context, key string) (*CachedResponse, error) {
+	val, err := rc.redisClient.Get(ctx, rc.prefix+"response:"+key).Bytes()
+
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var response CachedResponse
+
+	if err = json.Unmarshal(val, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// Set - сохраняет ответ по ключу и привязывает его к тегам. Ответ без срока жизни не сохраняется: множество тега
+// живет столько же, сколько самый долгий ответ в нем, и такой ответ нельзя было бы инвалидировать
+func (rc *ResponseCache) Set(ctx context.Context, key string, response *CachedResponse, ttl time.Duration, tags ...string) error {
+	if ttl < time.Millisecond {
+		return errors.New("response cache: ttl must be at least 1ms")
+	}
+
+	val, err := json.Marshal(response)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = rc.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, rc.prefix+"response:"+key, val, ttl)
+
+		for _, tag := range tags {
+			addTagScript.Eval(ctx, pipe, []string{rc.tagKey(tag)}, key, ttl.Milliseconds())
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+// InvalidateTags - удаляет из кэша все ответы, привязанные к тегам. Вызывается из сервисного кода после изменения данных
+func (rc *ResponseCache) InvalidateTags(ctx context.Context, tags ...string) error {
+	for _, tag := range tags {
+		keys, err := rc.redisClient.SMembers(ctx, rc.tagKey(tag)).Result()
+
+		if err != nil {
+			return err
+		}
+
+		toDelete := make([]string, 0, len(keys)+1)
+
+		for _, key := range keys {
+			toDelete = append(toDelete, rc.prefix+"response:"+key)
+		}
+
+		toDelete = append(toDelete, rc.tagKey(tag))
+
+		if err = rc.redisClient.Del(ctx, toDelete...).Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (rc *ResponseCache) tagKey(tag string) string {
+	return rc.prefix + "tag:" + tag
+}
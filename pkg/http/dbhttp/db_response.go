@@ -0,0 +1,30 @@
+package dbhttp
+
+import (
+	"github.com/ZhanibekTau/go-sdk/pkg/constants"
+	"github.com/ZhanibekTau/go-sdk/pkg/database/gorm/translator"
+	"github.com/ZhanibekTau/go-sdk/pkg/http/helpers"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// ErrorResponse - ошибка gorm или драйвера БД в ответе (см. translator.Translate), при конфликте сериализации и deadlock - Retry-After.
+// Отдельный пакет, чтобы helpers не тянул драйверы всех БД
+func ErrorResponse(c *gin.Context, err error) {
+	appException := translator.Translate(err)
+
+	if appException == nil {
+		return
+	}
+
+	if appException.Code == http.StatusServiceUnavailable {
+		c.Header(constants.RetryAfterHeaderName, "1")
+	}
+
+	helpers.AppExceptionResponse(c, appException)
+}
+
+func FormattedErrorResponse(c *gin.Context, err error) {
+	ErrorResponse(c, err)
+	helpers.FormattedResponse(c)
+}
@@ -0,0 +1,292 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"github.com/ZhanibekTau/go-sdk/pkg/constants"
+	"github.com/gin-gonic/gin"
+	"github.com/ugorji/go/codec"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Encoder - сериализация тела ответа в формат, выбранный по заголовку Accept
+type Encoder interface {
+	// Name - имя формата, например constants.JSON
+	Name() string
+	// MediaTypes - типы из Accept, которые обслуживает формат, первый из них - Content-Type ответа
+	MediaTypes() []string
+	Encode(v any) ([]byte, error)
+}
+
+var (
+	encodersMu sync.RWMutex
+	encoders   = []Encoder{JsonEncoder{}, XmlEncoder{}, MsgpackEncoder{}}
+)
+
+// RegisterEncoder - добавляет формат ответа или заменяет формат с тем же именем
+func RegisterEncoder(encoder Encoder) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+
+	for i, e := range encoders {
+		if e.Name() == encoder.Name() {
+			encoders[i] = encoder
+
+			return
+		}
+	}
+
+	encoders = append(encoders, encoder)
+}
+
+// NegotiateEncoder - выбирает формат по заголовку Accept с учетом q. Пустой Accept, */* или application/* в любом
+// месте заголовка (так запрашивают браузеры и большинство клиентов) - JSON, остальные форматы отдаются только
+// по явному запросу. Если ни один из форматов не подходит - тоже JSON
+func NegotiateEncoder(accept string) Encoder {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+
+	mediaTypes := parseAccept(accept)
+
+	for _, mediaType := range mediaTypes {
+		if mediaType == "*/*" || mediaType == "application/*" {
+			return encoders[0]
+		}
+	}
+
+	for _, mediaType := range mediaTypes {
+		for _, encoder := range encoders {
+			for _, supported := range encoder.MediaTypes() {
+				if supported == mediaType {
+					return encoder
+				}
+			}
+		}
+	}
+
+	return encoders[0]
+}
+
+// SupportedMediaTypes - все типы, которые можно запросить в Accept
+func SupportedMediaTypes() []string {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+
+	result := make([]string, 0)
+
+	for _, encoder := range encoders {
+		result = append(result, encoder.MediaTypes()...)
+	}
+
+	return result
+}
+
+// contentTypeFor - Content-Type ответа: формат ответа задает тип в JSON, например application/problem+json,
+// для XML суффикс меняется на +xml, для остальных форматов берется тип формата
+func contentTypeFor(formatterContentType string, encoder Encoder) string {
+	switch {
+	case encoder.Name() == constants.JSON:
+		return formatterContentType
+	case encoder.Name() == constants.XML && strings.HasSuffix(formatterContentType, "+json"):
+		return strings.TrimSuffix(formatterContentType, "+json") + "+xml"
+	}
+
+	return encoder.MediaTypes()[0]
+}
+
+// parseAccept - типы из заголовка Accept по убыванию q, типы с q=0 отбрасываются
+func parseAccept(accept string) []string {
+	type acceptItem struct {
+		mediaType string
+		q         float64
+	}
+
+	items := make([]acceptItem, 0)
+
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		item := acceptItem{mediaType: strings.ToLower(strings.TrimSpace(params[0])), q: 1}
+
+		for _, param := range params[1:] {
+			if key, value, ok := strings.Cut(strings.TrimSpace(param), "="); ok && key == "q" {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					item.q = q
+				}
+			}
+		}
+
+		if item.mediaType != "" && item.q > 0 {
+			items = append(items, item)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].q > items[j].q
+	})
+
+	result := make([]string, len(items))
+
+	for i, item := range items {
+		result[i] = item.mediaType
+	}
+
+	return result
+}
+
+// JsonEncoder - JSON
+type JsonEncoder struct{}
+
+func (e JsonEncoder) Name() string {
+	return constants.JSON
+}
+
+func (e JsonEncoder) MediaTypes() []string {
+	return []string{constants.ContentTypeJson, "application/problem+json", "text/json"}
+}
+
+func (e JsonEncoder) Encode(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// XmlEncoder - XML для интеграций, которые не умеют JSON
+type XmlEncoder struct{}
+
+func (e XmlEncoder) Name() string {
+	return constants.XML
+}
+
+func (e XmlEncoder) MediaTypes() []string {
+	return []string{constants.ContentTypeXml, "application/problem+xml", "text/xml"}
+}
+
+// Encode - map, срезы и nil верхнего уровня (например data без обертки в ProblemResponseFormatter) оборачиваются в <response>
+func (e XmlEncoder) Encode(v any) ([]byte, error) {
+	var err error
+
+	buffer := bytes.NewBufferString(xml.Header)
+	encoder := xml.NewEncoder(buffer)
+	root := xml.StartElement{Name: xml.Name{Local: "response"}}
+
+	switch value := xmlValue(v).(type) {
+	case nil:
+		err = encoder.EncodeElement(struct{}{}, root)
+	case XmlMap:
+		err = encoder.EncodeElement(value, root)
+	case []any:
+		err = encoder.EncodeElement(struct {
+			Items []any `xml:"item"`
+		}{value}, root)
+	default:
+		err = encoder.Encode(value)
+	}
+
+	if err == nil {
+		err = encoder.Close()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// xmlValue - map со строковыми ключами заменяются на XmlMap на любом уровне вложенности, в том числе внутри срезов.
+// Map внутри структур не заменяются, на такой ответ writeFormatted отдаст 500 в XML
+func xmlValue(value any) any {
+	switch typed := value.(type) {
+	case nil, XmlMap, []byte:
+		return value
+	case map[string]any:
+		return XmlMap(typed)
+	case gin.H:
+		return XmlMap(typed)
+	}
+
+	reflected := reflect.ValueOf(value)
+
+	switch reflected.Kind() {
+	case reflect.Pointer:
+		if !reflected.IsNil() && reflected.Elem().Kind() == reflect.Map {
+			return xmlValue(reflected.Elem().Interface())
+		}
+	case reflect.Map:
+		if reflected.Type().Key().Kind() != reflect.String {
+			return value
+		}
+
+		result := make(XmlMap, reflected.Len())
+
+		for iter := reflected.MapRange(); iter.Next(); {
+			result[iter.Key().String()] = iter.Value().Interface()
+		}
+
+		return result
+	case reflect.Slice, reflect.Array:
+		result := make([]any, reflected.Len())
+
+		for i := range result {
+			result[i] = xmlValue(reflected.Index(i).Interface())
+		}
+
+		return result
+	}
+
+	return value
+}
+
+// XmlMap - map для XML: encoding/xml не умеет map, ключи становятся элементами, вложенные map обрабатываются так же
+type XmlMap map[string]any
+
+func (m XmlMap) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(m))
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := xmlValue(m[key])
+
+		if value == nil {
+			continue
+		}
+
+		if err := e.EncodeElement(value, xml.StartElement{Name: xml.Name{Local: key}}); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// MsgpackEncoder - MessagePack, имена полей берутся из json тегов
+type MsgpackEncoder struct{}
+
+var msgpackHandle = &codec.MsgpackHandle{}
+
+func (e MsgpackEncoder) Name() string {
+	return constants.MsgPack
+}
+
+func (e MsgpackEncoder) MediaTypes() []string {
+	return []string{constants.ContentTypeMsgPack, "application/x-msgpack", "application/vnd.msgpack"}
+}
+
+func (e MsgpackEncoder) Encode(v any) ([]byte, error) {
+	var body []byte
+	err := codec.NewEncoderBytes(&body, msgpackHandle).Encode(v)
+
+	return body, err
+}
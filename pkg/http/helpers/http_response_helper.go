@@ -1,15 +1,13 @@
 package helpers
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/ZhanibekTau/go-sdk/pkg/config"
 	"github.com/ZhanibekTau/go-sdk/pkg/constants"
 	"github.com/ZhanibekTau/go-sdk/pkg/exception"
 	"github.com/getsentry/sentry-go"
+	sentrygin "github.com/getsentry/sentry-go/gin"
 	"github.com/gin-gonic/gin"
-	"github.com/mitchellh/mapstructure"
 	"log"
 	"net/http"
 	"strconv"
@@ -63,84 +61,92 @@ func FormattedSuccessResponse(c *gin.Context, data any) {
 	FormattedResponse(c)
 }
 
+// FormattedResponse Пишет ответ в формате ResponseFormatter текущего запроса, сериализация выбирается по заголовку Accept
 func FormattedResponse(c *gin.Context) {
-	start := time.Now()
+	// ответ уже записан (например, повтор сохраненного ответа или ошибка из middleware), повторно не пишем
+	if IsResponseWritten(c) {
+		return
+	}
 
-	appExceptionObject, exists := c.Get("exception")
-	fmt.Printf("%+v\n", appExceptionObject)
+	formatter := GetResponseFormatter(c)
+	encoder := NegotiateEncoder(c.GetHeader(constants.AcceptHeaderName))
+	appException, exists := GetAppException(c)
 
 	if !exists {
 		data, _ := c.Get("data")
-		response := struct {
-			Success bool        `json:"success"`
-			Data    interface{} `json:"data"`
-		}{
-			true,
-			data,
+		statusCode := http.StatusOK
+
+		if value, ex := c.Get("status_code"); ex {
+			statusCode = value.(int)
 		}
 
-		jsonBytes, err := json.Marshal(response)
+		writeFormatted(c, formatter, encoder, statusCode, formatter.Success(c, data), false)
 
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to marshal JSON"})
+		return
+	}
 
-			return
-		}
+	writeFormatted(c, formatter, encoder, appException.Code, formatter.Error(c, appException), true)
+	// после записи ответа, чтобы контекст из AddExceptionContext видел тело ответа
+	captureAppException(c, appException)
+}
 
-		c.Writer.Status()
-		statusCode, ex := c.Get("status_code")
+// GetAppException - ошибка текущего запроса, записанная через AppExceptionResponse или gin.Error
+func GetAppException(c *gin.Context) (*exception.AppException, bool) {
+	value, exists := c.Get("exception")
 
-		if !ex {
-			SetColors(c, http.StatusOK, start)
-			c.Data(http.StatusOK, "application/json", jsonBytes)
-		} else {
-			SetColors(c, statusCode.(int), start)
-			c.Data(statusCode.(int), "application/json", jsonBytes)
-		}
+	if !exists {
+		return nil, false
+	}
 
-		return
+	switch appException := value.(type) {
+	case *exception.AppException:
+		return appException, appException != nil
+	case exception.AppException:
+		return &appException, true
+	case error:
+		return exception.Wrap(appException, http.StatusInternalServerError, nil), true
 	}
 
-	appException := exception.AppException{}
-	mapstructure.Decode(appExceptionObject, &appException)
-	fmt.Printf("%+v\n", appException)
-	serviceName := "UNKNOWN (maybe you not used RequestMiddleware)"
-	requestId := "UNKNOWN (maybe you not used RequestMiddleware)"
-	value, exists := c.Get("app_info")
-
-	if exists {
-		appInfo := value.(*config.AppInfo)
-		serviceName = appInfo.ServiceName
-		requestId = appInfo.RequestId
+	return nil, false
+}
+
+// writeFormatted - сериализует и пишет тело ответа. Если тело не сериализуется в выбранный формат (например, структура
+// с map в XML), клиент получает 500 в том же формате, ошибка записывается в контекст для LoggerMiddleware
+func writeFormatted(c *gin.Context, formatter ResponseFormatter, encoder Encoder, statusCode int, body any, isError bool) {
+	bytes, err := encoder.Encode(body)
+
+	if err != nil {
+		encodeException := exception.NewInternalServerAppException(errors.New("failed to encode response to "+encoder.Name()+": "+err.Error()), nil)
+		c.Set("exception", encodeException)
+		statusCode, isError = encodeException.Code, true
+		bytes, err = encoder.Encode(formatter.Error(c, exception.NewInternalServerAppException(errors.New("failed to encode response"), nil)))
 	}
 
-	responseData := gin.H{
-		"status":       appException.Code,
-		"error":        appException.GetErrorType(),
-		"message":      appException.Error.Error(),
-		"request_id":   requestId,
-		"hostname":     serviceName,
-		"service_code": appException.ServiceCode,
-		"details":      appException.Context,
+	if err != nil {
+		c.String(http.StatusInternalServerError, "failed to encode response")
+
+		return
 	}
 
-	response := struct {
-		Success bool        `json:"success"`
-		Data    interface{} `json:"data"`
-	}{
-		false,
-		responseData,
+	c.Data(statusCode, contentTypeFor(formatter.ContentType(isError), encoder), bytes)
+}
+
+// captureAppException - отправляет ошибку в сентри с заголовками и query параметрами запроса
+func captureAppException(c *gin.Context, appException *exception.AppException) {
+	if IsExceptionCaptured(c) {
+		return
 	}
 
-	jsonBytes, err := json.Marshal(response)
+	MarkExceptionCaptured(c)
 
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to marshal JSON"})
+	// хаб запроса из sentrygin содержит контекст, добавленный middleware (например, тела запроса и ответа)
+	hub := sentrygin.GetHubFromContext(c)
 
-		return
+	if hub == nil {
+		hub = sentry.CurrentHub()
 	}
 
-	sentry.WithScope(func(scope *sentry.Scope) {
+	hub.WithScope(func(scope *sentry.Scope) {
 		// Добавляем заголовки запроса
 		mapHeaders := make(map[string]any)
 		for key, values := range c.Request.Header {
@@ -160,15 +166,57 @@ func FormattedResponse(c *gin.Context) {
 		scope.SetContext("query", mapQueries)
 
 		// Захватываем ошибку
-		scope.SetContext("error", responseData)
+		scope.SetContext("error", errorData(c, appException))
 
-		sentry.CaptureMessage("Http Status Code  - " + strconv.Itoa(appException.Code) + ". Url - " + c.Request.URL.Path)
+		ApplyExceptionContexts(c, scope)
+
+		hub.CaptureMessage("Http Status Code  - " + strconv.Itoa(appException.Code) + ". Url - " + c.Request.URL.Path)
 	})
+}
+
+// IsResponseWritten - записан ли уже ответ. Writer из timeout middleware буферизирует тело, поэтому проверяется и размер
+func IsResponseWritten(c *gin.Context) bool {
+	return c.Writer.Written() || c.Writer.Size() > 0 || c.GetBool("response_written")
+}
+
+// MarkResponseWritten - помечает ответ записанным, если он без тела (например, 304) и записан в обход FormattedResponse
+func MarkResponseWritten(c *gin.Context) {
+	c.Set("response_written", true)
+}
+
+// AddExceptionContext - добавляет функцию, которая дополняет событие сентри при отправке ошибки запроса
+// (например, телами запроса и ответа). Вызывается после записи ответа
+func AddExceptionContext(c *gin.Context, addContext func(scope *sentry.Scope)) {
+	c.Set("exception_contexts", append(exceptionContexts(c), addContext))
+}
+
+// ApplyExceptionContexts - дополняет событие сентри контекстом, добавленным через AddExceptionContext
+func ApplyExceptionContexts(c *gin.Context, scope *sentry.Scope) {
+	for _, addContext := range exceptionContexts(c) {
+		addContext(scope)
+	}
+}
+
+func exceptionContexts(c *gin.Context) []func(scope *sentry.Scope) {
+	value, _ := c.Get("exception_contexts")
+	contexts, _ := value.([]func(scope *sentry.Scope))
+
+	return contexts
+}
+
+// MarkExceptionCaptured - ошибка запроса уже отправлена в сентри (например, паника со стеком), FormattedResponse
+// и LoggerMiddleware ее не отправляют
+func MarkExceptionCaptured(c *gin.Context) {
+	c.Set("exception_captured", true)
+}
 
-	SetColors(c, appException.Code, start)
-	c.Data(appException.Code, "application/json", jsonBytes)
+// IsExceptionCaptured - отправлена ли ошибка запроса в сентри
+func IsExceptionCaptured(c *gin.Context) bool {
+	return c.GetBool("exception_captured")
 }
 
+// SetColors Цветной лог запроса.
+// Deprecated: access лог пишет middleware.AccessLogMiddleware
 func SetColors(c *gin.Context, statusCode int, start time.Time) {
 	methodColor := constants.Green
 	if statusCode >= 400 && statusCode < 500 {
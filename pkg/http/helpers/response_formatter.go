@@ -0,0 +1,159 @@
+package helpers
+
+import (
+	"encoding/xml"
+	"github.com/ZhanibekTau/go-sdk/pkg/config"
+	"github.com/ZhanibekTau/go-sdk/pkg/exception"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// ResponseFormatter - формат тела ответа, который пишет FormattedResponse
+type ResponseFormatter interface {
+	// Success - тело успешного ответа
+	Success(c *gin.Context, data any) any
+	// Error - тело ответа с ошибкой
+	Error(c *gin.Context, appException *exception.AppException) any
+	// ContentType - Content-Type ответа в JSON
+	ContentType(isError bool) string
+}
+
+// SetResponseFormatter - формат ответа для текущего запроса, обычно задается через middleware.ResponseFormatterMiddleware на роутер или группу
+func SetResponseFormatter(c *gin.Context, formatter ResponseFormatter) {
+	c.Set("response_formatter", formatter)
+}
+
+// GetResponseFormatter - формат ответа текущего запроса, по умолчанию DefaultResponseFormatter
+func GetResponseFormatter(c *gin.Context) ResponseFormatter {
+	if value, exists := c.Get("response_formatter"); exists {
+		if formatter, ok := value.(ResponseFormatter); ok {
+			return formatter
+		}
+	}
+
+	return DefaultResponseFormatter{}
+}
+
+// Response - стандартный формат ответа {success, data}
+type Response struct {
+	XMLName xml.Name `json:"-" xml:"response"`
+	Success bool     `json:"success" xml:"success"`
+	Data    any      `json:"data" xml:"data"`
+}
+
+// MarshalXML - data приводится через xmlValue, иначе map в data не сериализовать
+func (r Response) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	// отдельный тип без MarshalXML, чтобы не уйти в рекурсию
+	type response Response
+
+	value := response(r)
+	value.Data = xmlValue(r.Data)
+	// encoding/xml передает в MarshalXML имя типа, а не XMLName
+	start.Name = xml.Name{Local: "response"}
+
+	return e.EncodeElement(value, start)
+}
+
+// DefaultResponseFormatter - стандартный формат ответа, ошибка в data: {status, error, message, request_id, hostname, service_code, details}
+type DefaultResponseFormatter struct{}
+
+func (f DefaultResponseFormatter) Success(_ *gin.Context, data any) any {
+	return Response{Success: true, Data: data}
+}
+
+func (f DefaultResponseFormatter) Error(c *gin.Context, appException *exception.AppException) any {
+	return Response{Success: false, Data: errorData(c, appException)}
+}
+
+func (f DefaultResponseFormatter) ContentType(_ bool) string {
+	return "application/json"
+}
+
+// NewProblemResponseFormatter - формат ошибок по RFC 7807 (application/problem+json).
+// typeBaseUri - префикс ссылки на описание ошибки в поле type, к нему добавляется тип ошибки. Пусто - "about:blank"
+func NewProblemResponseFormatter(typeBaseUri string) *ProblemResponseFormatter {
+	return &ProblemResponseFormatter{typeBaseUri: typeBaseUri}
+}
+
+// ProblemResponseFormatter - формат ошибок по RFC 7807, успешный ответ отдается без обертки
+type ProblemResponseFormatter struct {
+	typeBaseUri string
+}
+
+// Problem - описание ошибки по RFC 7807, поля после instance - расширения
+type Problem struct {
+	XMLName     xml.Name `json:"-" xml:"urn:ietf:rfc:7807 problem"`
+	Type        string   `json:"type" xml:"type"`
+	Title       string   `json:"title" xml:"title"`
+	Status      int      `json:"status" xml:"status"`
+	Detail      string   `json:"detail" xml:"detail"`
+	Instance    string   `json:"instance" xml:"instance"`
+	Error       string   `json:"error" xml:"error"`
+	RequestId   string   `json:"request_id" xml:"request_id"`
+	Hostname    string   `json:"hostname" xml:"hostname"`
+	ServiceCode int      `json:"service_code" xml:"service_code"`
+	Details     XmlMap   `json:"details,omitempty" xml:"details,omitempty"`
+}
+
+func (f *ProblemResponseFormatter) Success(_ *gin.Context, data any) any {
+	return data
+}
+
+func (f *ProblemResponseFormatter) Error(c *gin.Context, appException *exception.AppException) any {
+	serviceName, requestId := requestInfo(c)
+	problemType := "about:blank"
+
+	if f.typeBaseUri != "" {
+		problemType = f.typeBaseUri + appException.GetErrorType()
+	}
+
+	return Problem{
+		Type:        problemType,
+		Title:       http.StatusText(appException.Code),
+		Status:      appException.Code,
+		Detail:      appException.Error(),
+		Instance:    c.Request.URL.RequestURI(),
+		Error:       appException.GetErrorType(),
+		RequestId:   requestId,
+		Hostname:    serviceName,
+		ServiceCode: appException.ServiceCode,
+		Details:     XmlMap(appException.Context),
+	}
+}
+
+func (f *ProblemResponseFormatter) ContentType(isError bool) string {
+	if isError {
+		return "application/problem+json"
+	}
+
+	return "application/json"
+}
+
+// errorData - данные ошибки стандартного формата
+func errorData(c *gin.Context, appException *exception.AppException) XmlMap {
+	serviceName, requestId := requestInfo(c)
+
+	return XmlMap{
+		"status":       appException.Code,
+		"error":        appException.GetErrorType(),
+		"message":      appException.Error(),
+		"request_id":   requestId,
+		"hostname":     serviceName,
+		"service_code": appException.ServiceCode,
+		"details":      XmlMap(appException.Context),
+	}
+}
+
+// requestInfo - имя сервиса и request id из AppInfo
+func requestInfo(c *gin.Context) (serviceName string, requestId string) {
+	serviceName = "UNKNOWN (maybe you not used RequestMiddleware)"
+	requestId = "UNKNOWN (maybe you not used RequestMiddleware)"
+
+	if value, exists := c.Get("app_info"); exists {
+		appInfo := value.(*config.AppInfo)
+		serviceName = appInfo.ServiceName
+		requestId = appInfo.RequestId
+	}
+
+	return serviceName, requestId
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"github.com/ZhanibekTau/go-sdk/pkg/constants"
+	gin2 "github.com/ZhanibekTau/go-sdk/pkg/gin"
+	"github.com/ZhanibekTau/go-sdk/pkg/http/helpers"
+	"github.com/ZhanibekTau/go-sdk/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/mattn/go-isatty"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"os"
+	"time"
+)
+
+// AccessLogConfig - настройки AccessLogMiddleware
+type AccessLogConfig struct {
+	// Format - logger.FormatJson (по умолчанию) или logger.FormatLogfmt
+	Format string
+	// Output - куда писать лог, по умолчанию os.Stdout
+	Output io.Writer
+	// SkipPaths - пути, которые не логируются (health check, метрики)
+	SkipPaths []string
+}
+
+// AccessLogMiddleware Middleware для access лога: одна структурированная запись на запрос.
+// Цвет статуса добавляется только в logfmt и только если вывод идет в терминал
+func AccessLogMiddleware(cfg AccessLogConfig) gin.HandlerFunc {
+	if cfg.Format == "" {
+		cfg.Format = logger.FormatJson
+	}
+
+	if cfg.Output == nil {
+		cfg.Output = os.Stdout
+	}
+
+	skip := make(map[string]bool, len(cfg.SkipPaths))
+
+	for _, path := range cfg.SkipPaths {
+		skip[path] = true
+	}
+
+	colored := false
+
+	if file, ok := cfg.Output.(*os.File); ok && cfg.Format == logger.FormatLogfmt {
+		colored = isatty.IsTerminal(file.Fd()) || isatty.IsCygwinTerminal(file.Fd())
+	}
+
+	return func(c *gin.Context) {
+		if skip[c.Request.URL.Path] {
+			c.Next()
+
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+
+		appInfo := gin2.GetAppInfo(c)
+		status := c.Writer.Status()
+		size := c.Writer.Size()
+
+		if size < 0 {
+			size = 0
+		}
+
+		errorType := ""
+
+		if appException, ok := helpers.GetAppException(c); ok {
+			errorType = appException.GetErrorType()
+		}
+
+		traceId := ""
+
+		if spanContext := trace.SpanFromContext(c.Request.Context()).SpanContext(); spanContext.HasTraceID() {
+			traceId = spanContext.TraceID().String()
+		}
+
+		statusColor := ""
+
+		if colored {
+			statusColor = accessLogStatusColor(status)
+		}
+
+		logger.WriteStructured(cfg.Output, cfg.Format, []logger.Field{
+			{Key: "time", Value: start.Format(time.RFC3339Nano)},
+			{Key: "service", Value: appInfo.ServiceName},
+			{Key: "method", Value: c.Request.Method},
+			{Key: "route", Value: c.FullPath()},
+			{Key: "path", Value: c.Request.URL.Path},
+			{Key: "status", Value: status, Color: statusColor},
+			{Key: "bytes", Value: size},
+			{Key: "latency_ms", Value: float64(time.Since(start).Microseconds()) / 1000},
+			{Key: "client_ip", Value: c.ClientIP()},
+			{Key: "request_id", Value: appInfo.RequestId},
+			{Key: "user_id", Value: appInfo.UserId},
+			{Key: "client_id", Value: appInfo.ClientId},
+			{Key: "trace_id", Value: traceId},
+			{Key: "error_type", Value: errorType},
+		})
+	}
+}
+
+func accessLogStatusColor(status int) string {
+	if status >= 500 {
+		return constants.Red
+	}
+
+	if status >= 400 {
+		return constants.Yellow
+	}
+
+	return constants.Green
+}
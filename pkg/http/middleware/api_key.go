@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"github.com/ZhanibekTau/go-sdk/pkg/auth"
+	"github.com/ZhanibekTau/go-sdk/pkg/constants"
+	gin2 "github.com/ZhanibekTau/go-sdk/pkg/gin"
+	"github.com/ZhanibekTau/go-sdk/pkg/http/helpers"
+	span2 "github.com/ZhanibekTau/go-sdk/pkg/tracer/span"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+// ApiKeyMiddleware Middleware для аутентификации по API ключу из заголовка X-Api-Key
+func ApiKeyMiddleware(store auth.IApiKeyStore, scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(constants.ApiKeyHeaderName)
+
+		if key == "" {
+			helpers.FormattedTextErrorResponse(c, http.StatusUnauthorized, "api key is required", nil)
+			c.Abort()
+
+			return
+		}
+
+		apiKey, err := store.GetByHash(c.Request.Context(), auth.HashApiKey(key))
+
+		if err != nil {
+			helpers.FormattedErrorResponse(c, http.StatusInternalServerError, err, nil)
+			c.Abort()
+
+			return
+		}
+
+		if apiKey == nil || apiKey.IsExpired() {
+			helpers.FormattedTextErrorResponse(c, http.StatusUnauthorized, "api key is invalid or expired", nil)
+			c.Abort()
+
+			return
+		}
+
+		if !apiKey.HasScopes(scopes...) {
+			helpers.FormattedTextErrorResponse(c, http.StatusForbidden, "api key has no access", map[string]any{"scopes": scopes})
+			c.Abort()
+
+			return
+		}
+
+		setAuthClient(c, apiKey.ClientId)
+		c.Set("api_key", apiKey)
+		c.Next()
+	}
+}
+
+// setAuthClient - записывает аутентифицированного клиента в AppInfo и текущий спан
+func setAuthClient(c *gin.Context, clientId string) {
+	appInfo := gin2.GetAppInfo(c)
+	appInfo.ClientId = clientId
+	c.Set("app_info", appInfo)
+
+	trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String(span2.AttributeClientId, clientId))
+}
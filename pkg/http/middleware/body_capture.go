@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"bytes"
+	gin2 "github.com/ZhanibekTau/go-sdk/pkg/gin"
+	"github.com/ZhanibekTau/go-sdk/pkg/http/helpers"
+	"github.com/ZhanibekTau/go-sdk/pkg/logger"
+	"github.com/ZhanibekTau/go-sdk/pkg/redact"
+	span2 "github.com/ZhanibekTau/go-sdk/pkg/tracer/span"
+	"github.com/getsentry/sentry-go"
+	"github.com/gin-gonic/gin"
+	"github.com/gookit/goutil/netutil/httpctype"
+	"github.com/gookit/goutil/netutil/httpheader"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"strings"
+)
+
+// BodyCaptureConfig - настройки BodyCaptureMiddleware
+type BodyCaptureConfig struct {
+	// MaxBytes - сколько байт тела запроса и ответа сохраняется, по умолчанию 4096
+	MaxBytes int
+	// Routes - шаблоны маршрутов (c.FullPath()), для которых сохраняются тела, пусто - для всех
+	Routes []string
+	// StatusClasses - классы статусов ответа (4 - 4xx, 5 - 5xx), для которых сохраняются тела, пусто - для всех
+	StatusClasses []int
+	// DenyList - поля, значения которых скрываются, по умолчанию redact.DefaultDenyList
+	DenyList []string
+}
+
+// BodyCaptureMiddleware Middleware для сохранения тел запроса и ответа для отладки. Тела обрезаются до MaxBytes,
+// поля из DenyList скрываются, результат пишется в лог, в текущий спан и в контекст сентри. Multipart тела пропускаются
+func BodyCaptureMiddleware(cfg BodyCaptureConfig) gin.HandlerFunc {
+	if cfg.MaxBytes == 0 {
+		cfg.MaxBytes = 4096
+	}
+
+	if cfg.DenyList == nil {
+		cfg.DenyList = redact.DefaultDenyList
+	}
+
+	routes := make(map[string]bool, len(cfg.Routes))
+
+	for _, route := range cfg.Routes {
+		routes[route] = true
+	}
+
+	return func(c *gin.Context) {
+		if len(routes) > 0 && !routes[c.FullPath()] {
+			c.Next()
+
+			return
+		}
+
+		requestBody := ""
+		contentType := c.GetHeader(httpheader.ContentType)
+
+		// нет смысла копировать тело запроса при наличии файла
+		if !strings.HasPrefix(contentType, httpctype.MIMEDataForm) && c.Request.Body != nil {
+			// читается не больше лимита, остаток тела обработчик дочитает из исходного Body
+			bodyBytes, _ := io.ReadAll(io.LimitReader(c.Request.Body, int64(cfg.MaxBytes)+1))
+			c.Request.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(bodyBytes), c.Request.Body), c.Request.Body}
+			requestBody = captureBody(bodyBytes, contentType, cfg)
+		}
+
+		writer := newBodyWriter(c, cfg.MaxBytes+1)
+		captureResponse := func() string {
+			if responseType := c.Writer.Header().Get(httpheader.ContentType); !strings.HasPrefix(responseType, "multipart/") {
+				return captureBody(writer.body.Bytes(), responseType, cfg)
+			}
+
+			return ""
+		}
+
+		// ошибка отправляется в сентри из FormattedResponse, в том числе вызванного обработчиком,
+		// поэтому тела добавляются в событие в момент отправки
+		helpers.AddExceptionContext(c, func(scope *sentry.Scope) {
+			if !matchStatusClass(c.Writer.Status(), cfg.StatusClasses) {
+				return
+			}
+
+			scope.SetContext("request_body", map[string]any{"body": requestBody})
+			scope.SetContext("response_body", map[string]any{"body": captureResponse()})
+		})
+
+		c.Next()
+		helpers.FormattedResponse(c)
+
+		if !matchStatusClass(c.Writer.Status(), cfg.StatusClasses) {
+			return
+		}
+
+		responseBody := captureResponse()
+
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(
+			attribute.String(span2.AttributeReqBody, requestBody),
+			attribute.String(span2.AttributeRespBody, responseBody),
+		)
+
+		logger.FormattedLogWithAppInfo(gin2.GetAppInfo(c), "request body: "+requestBody+"; response body: "+responseBody)
+	}
+}
+
+// captureBody - тело со скрытыми полями, обрезанное до MaxBytes. Тело длиннее лимита не разобрать целиком,
+// поля в нем скрываются по началу JSON. Тела, в которых поля скрыть нельзя, не сохраняются
+func captureBody(body []byte, contentType string, cfg BodyCaptureConfig) string {
+	if len(body) > 0 && !redact.CanMask(contentType) {
+		return string(redact.Omitted(contentType))
+	}
+
+	if len(body) > cfg.MaxBytes && !strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		return string(redact.MaskJsonPrefix(body[:cfg.MaxBytes], cfg.DenyList)) + "...(truncated)"
+	}
+
+	return string(redact.Truncate(redact.MaskBody(body, contentType, cfg.DenyList), cfg.MaxBytes))
+}
+
+func matchStatusClass(status int, classes []int) bool {
+	if len(classes) == 0 {
+		return true
+	}
+
+	for _, class := range classes {
+		if status/100 == class {
+			return true
+		}
+	}
+
+	return false
+}
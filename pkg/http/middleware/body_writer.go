@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+)
+
+// newBodyWriter - подменяет writer контекста на копирующий тело ответа. limit - максимум копируемых байт, 0 - без ограничения
+func newBodyWriter(c *gin.Context, limit int) *bodyWriter {
+	writer := &bodyWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}, limit: limit}
+	c.Writer = writer
+
+	return writer
+}
+
+// bodyWriter - gin.ResponseWriter, который копирует тело ответа
+type bodyWriter struct {
+	gin.ResponseWriter
+	body      *bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (w *bodyWriter) Write(b []byte) (int, error) {
+	w.capture(b)
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *bodyWriter) capture(b []byte) {
+	if w.limit > 0 && w.body.Len()+len(b) > w.limit {
+		w.body.Write(b[:w.limit-w.body.Len()])
+		w.truncated = true
+
+		return
+	}
+
+	w.body.Write(b)
+}
+
+// newBufferWriter - подменяет writer контекста на буферизирующий ответ целиком, до flush ничего не отправляется клиенту
+func newBufferWriter(c *gin.Context) *bufferWriter {
+	writer := &bufferWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+	c.Writer = writer
+
+	return writer
+}
+
+// bufferWriter - gin.ResponseWriter, который копит статус и тело ответа, заголовки пишутся в исходный writer
+type bufferWriter struct {
+	gin.ResponseWriter
+	body    *bytes.Buffer
+	status  int
+	written bool
+}
+
+func (w *bufferWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *bufferWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *bufferWriter) Write(b []byte) (int, error) {
+	w.written = true
+
+	return w.body.Write(b)
+}
+
+func (w *bufferWriter) WriteString(s string) (int, error) {
+	w.written = true
+
+	return w.body.WriteString(s)
+}
+
+func (w *bufferWriter) Status() int {
+	if w.status == 0 {
+		return w.ResponseWriter.Status()
+	}
+
+	return w.status
+}
+
+func (w *bufferWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+
+	return w.body.Len()
+}
+
+func (w *bufferWriter) Written() bool {
+	return w.written
+}
+
+// flush - возвращает исходный writer в контекст и отправляет в него накопленный ответ
+func (w *bufferWriter) flush(c *gin.Context) {
+	c.Writer = w.ResponseWriter
+	c.Writer.WriteHeader(w.Status())
+
+	if w.body.Len() > 0 {
+		c.Writer.Write(w.body.Bytes())
+	} else if w.written {
+		c.Writer.WriteHeaderNow()
+	}
+}
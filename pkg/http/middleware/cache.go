@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/ZhanibekTau/go-sdk/pkg/constants"
+	gin2 "github.com/ZhanibekTau/go-sdk/pkg/gin"
+	"github.com/ZhanibekTau/go-sdk/pkg/http/cache"
+	"github.com/ZhanibekTau/go-sdk/pkg/http/helpers"
+	"github.com/ZhanibekTau/go-sdk/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheConfig - настройки CacheMiddleware
+type CacheConfig struct {
+	// Ttl - время жизни ответа в кэше, по умолчанию минута
+	Ttl time.Duration
+	// Headers - заголовки запроса, входящие в ключ кэша, по умолчанию Accept, Accept-Language и City-Id
+	Headers []string
+	// IgnoreQuery - не учитывать query параметры в ключе кэша
+	IgnoreQuery bool
+	// Tags - теги, по которым ответы группы инвалидируются через ResponseCache.InvalidateTags
+	Tags []string
+	// TagsFunc - теги, зависящие от запроса, например тег конкретной сущности по id из пути
+	TagsFunc func(c *gin.Context) []string
+	// Shared - ответ не зависит от пользователя: ключ кэша общий для всех. По умолчанию в ключ входит
+	// клиент или пользователь из AppInfo
+	Shared bool
+}
+
+// CacheMiddleware Middleware для кэширования ответов GET запросов в редисе. Кэшируется отформатированный
+// ответ со статусом 200, клиенту отдаются ETag и Last-Modified, на If-None-Match отвечает 304
+func CacheMiddleware(responseCache *cache.ResponseCache, cfg CacheConfig) gin.HandlerFunc {
+	if cfg.Ttl <= 0 {
+		cfg.Ttl = time.Minute
+	}
+
+	if cfg.Headers == nil {
+		cfg.Headers = []string{constants.AcceptHeaderName, constants.LanguageHeaderName, constants.CityHeaderName}
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+
+			return
+		}
+
+		ctx := c.Request.Context()
+		key := cacheKey(c, cfg)
+		cached, err := responseCache.Get(ctx, key)
+
+		if err != nil {
+			logger.FormattedErrorWithAppInfo(gin2.GetAppInfo(c), "response cache error: "+err.Error())
+			c.Next()
+
+			return
+		}
+
+		if cached != nil {
+			c.Header(constants.CacheHeaderName, "HIT")
+			writeCachedResponse(c, cached)
+			c.Abort()
+
+			return
+		}
+
+		writer := newBufferWriter(c)
+		c.Next()
+		helpers.FormattedResponse(c)
+
+		_, hasException := c.Get("exception")
+
+		if writer.Status() == http.StatusOK && !hasException {
+			response := &cache.CachedResponse{
+				StatusCode:   writer.Status(),
+				ContentType:  c.Writer.Header().Get("Content-Type"),
+				Body:         writer.body.Bytes(),
+				ETag:         etag(writer.body.Bytes()),
+				LastModified: time.Now().UTC().Truncate(time.Second),
+			}
+
+			tags := cfg.Tags
+
+			if cfg.TagsFunc != nil {
+				tags = append(append([]string{}, tags...), cfg.TagsFunc(c)...)
+			}
+
+			if err = responseCache.Set(ctx, key, response, cfg.Ttl, tags...); err != nil {
+				logger.FormattedErrorWithAppInfo(gin2.GetAppInfo(c), "response cache error: "+err.Error())
+			}
+
+			c.Header(constants.ETagHeaderName, response.ETag)
+			c.Header(constants.LastModifiedHeaderName, response.LastModified.Format(http.TimeFormat))
+
+			// у клиента может быть актуальная версия ответа, закэшированная до истечения ttl
+			if isNotModified(c, response) {
+				writer.status = http.StatusNotModified
+				writer.body.Reset()
+			}
+		}
+
+		c.Header(constants.CacheHeaderName, "MISS")
+		writer.flush(c)
+	}
+}
+
+// writeCachedResponse - отдает ответ из кэша или 304, если у клиента актуальная версия
+func writeCachedResponse(c *gin.Context, cached *cache.CachedResponse) {
+	c.Header(constants.ETagHeaderName, cached.ETag)
+	c.Header(constants.LastModifiedHeaderName, cached.LastModified.Format(http.TimeFormat))
+
+	if isNotModified(c, cached) {
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		helpers.MarkResponseWritten(c)
+
+		return
+	}
+
+	c.Data(cached.StatusCode, cached.ContentType, cached.Body)
+}
+
+func isNotModified(c *gin.Context, cached *cache.CachedResponse) bool {
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" {
+		for _, tag := range strings.Split(ifNoneMatch, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+
+			if tag == "*" || tag == cached.ETag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if ifModifiedSince := c.GetHeader("If-Modified-Since"); ifModifiedSince != "" {
+		if since, err := http.ParseTime(ifModifiedSince); err == nil {
+			return !cached.LastModified.After(since)
+		}
+	}
+
+	return false
+}
+
+// cacheKey - ключ из клиента или пользователя, пути, query и выбранных заголовков запроса
+func cacheKey(c *gin.Context, cfg CacheConfig) string {
+	hash := sha256.New()
+
+	if !cfg.Shared {
+		hash.Write([]byte(cacheScope(c) + "\n"))
+	}
+
+	hash.Write([]byte(c.Request.URL.Path))
+
+	if !cfg.IgnoreQuery {
+		// Encode сортирует параметры по ключу, поэтому порядок параметров в запросе не важен
+		hash.Write([]byte("?" + c.Request.URL.Query().Encode()))
+	}
+
+	for _, header := range cfg.Headers {
+		hash.Write([]byte("\n" + header + ":" + c.GetHeader(header)))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// cacheScope - владелец ответа: клиент или пользователь, анонимные запросы делят один кэш
+func cacheScope(c *gin.Context) string {
+	appInfo := gin2.GetAppInfo(c)
+
+	if appInfo.ClientId != "" {
+		return "client:" + appInfo.ClientId
+	}
+
+	if appInfo.UserId != 0 {
+		return "user:" + strconv.Itoa(appInfo.UserId)
+	}
+
+	return "anonymous"
+}
+
+func etag(body []byte) string {
+	hash := sha256.Sum256(body)
+
+	return `"` + hex.EncodeToString(hash[:16]) + `"`
+}
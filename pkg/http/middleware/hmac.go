@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/ZhanibekTau/go-sdk/pkg/auth"
+	gin2 "github.com/ZhanibekTau/go-sdk/pkg/gin"
+	"github.com/ZhanibekTau/go-sdk/pkg/http/helpers"
+	"github.com/ZhanibekTau/go-sdk/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// HmacMiddleware Middleware для проверки HMAC подписи запроса (см. auth.HmacSigner)
+func HmacMiddleware(verifier *auth.HmacVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientId, err := verifier.Verify(c.Request)
+
+		if errors.Is(err, auth.ErrBodyTooLarge) {
+			helpers.FormattedErrorResponse(c, http.StatusRequestEntityTooLarge, err, nil)
+			c.Abort()
+
+			return
+		}
+
+		if err != nil && !isHmacError(err) {
+			// текст внутренней ошибки (редис, хранилище секретов) клиенту не отдается
+			logger.FormattedErrorWithAppInfo(gin2.GetAppInfo(c), "hmac verify error: "+err.Error())
+			helpers.FormattedTextErrorResponse(c, http.StatusInternalServerError, "request signature could not be verified", nil)
+			c.Abort()
+
+			return
+		}
+
+		if err != nil {
+			helpers.FormattedErrorResponse(c, http.StatusUnauthorized, err, nil)
+			c.Abort()
+
+			return
+		}
+
+		setAuthClient(c, clientId)
+		c.Next()
+	}
+}
+
+func isHmacError(err error) bool {
+	return errors.Is(err, auth.ErrSignatureMissing) ||
+		errors.Is(err, auth.ErrSignatureInvalid) ||
+		errors.Is(err, auth.ErrTimestampInvalid) ||
+		errors.Is(err, auth.ErrUnknownClient) ||
+		errors.Is(err, auth.ErrReplayedRequest)
+}
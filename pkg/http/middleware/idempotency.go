@@ -0,0 +1,244 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/ZhanibekTau/go-sdk/pkg/constants"
+	gin2 "github.com/ZhanibekTau/go-sdk/pkg/gin"
+	"github.com/ZhanibekTau/go-sdk/pkg/http/helpers"
+	"github.com/ZhanibekTau/go-sdk/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	idempotencyProcessing = "processing"
+	idempotencyCompleted  = "completed"
+)
+
+// IdempotencyConfig - настройки IdempotencyMiddleware
+type IdempotencyConfig struct {
+	// Ttl - сколько хранится сохраненный ответ, по умолчанию сутки
+	Ttl time.Duration
+	// LockTtl - сколько запрос считается выполняющимся, по умолчанию минута
+	LockTtl time.Duration
+	// Prefix - префикс ключей в редисе
+	Prefix string
+	// Methods - методы, для которых учитывается заголовок, по умолчанию POST и PATCH
+	Methods []string
+	// MaxBodyBytes - максимальный размер тела запроса с ключом, по умолчанию 10 МБ, тело больше - 413
+	MaxBodyBytes int
+}
+
+// errIdempotentBodyTooLarge - тело запроса с ключом больше IdempotencyConfig.MaxBodyBytes
+var errIdempotentBodyTooLarge = errors.New("request body is too large for idempotent request")
+
+// idempotencyRecord - запись о запросе в редисе
+type idempotencyRecord struct {
+	State       string `json:"state"`
+	Fingerprint string `json:"fingerprint"`
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// IdempotencyMiddleware Middleware для заголовка Idempotency-Key. Ответ на первый запрос (в том виде, как его
+// записал FormattedResponse) сохраняется в редис, повтор с тем же ключом получает сохраненный ответ
+func IdempotencyMiddleware(redisClient *redis.Client, cfg IdempotencyConfig) gin.HandlerFunc {
+	if cfg.Ttl == 0 {
+		cfg.Ttl = 24 * time.Hour
+	}
+
+	if cfg.LockTtl == 0 {
+		cfg.LockTtl = time.Minute
+	}
+
+	if cfg.Prefix == "" {
+		cfg.Prefix = "idempotency:"
+	}
+
+	if len(cfg.Methods) == 0 {
+		cfg.Methods = []string{http.MethodPost, http.MethodPatch}
+	}
+
+	if cfg.MaxBodyBytes == 0 {
+		cfg.MaxBodyBytes = 10 << 20
+	}
+
+	return func(c *gin.Context) {
+		idempotencyKey := c.GetHeader(constants.IdempotencyKeyHeaderName)
+
+		if idempotencyKey == "" || !containsString(cfg.Methods, c.Request.Method) {
+			c.Next()
+
+			return
+		}
+
+		fingerprint, err := requestFingerprint(c, cfg.MaxBodyBytes)
+
+		if errors.Is(err, errIdempotentBodyTooLarge) {
+			helpers.FormattedErrorResponse(c, http.StatusRequestEntityTooLarge, err, nil)
+			c.Abort()
+
+			return
+		}
+
+		if err != nil {
+			helpers.FormattedErrorResponse(c, http.StatusInternalServerError, err, nil)
+			c.Abort()
+
+			return
+		}
+
+		ctx := c.Request.Context()
+		key := cfg.Prefix + idempotencyScope(c) + ":" + idempotencyKey
+		lock, _ := json.Marshal(idempotencyRecord{State: idempotencyProcessing, Fingerprint: fingerprint})
+		isNew, err := redisClient.SetNX(ctx, key, lock, cfg.LockTtl).Result()
+
+		if err != nil {
+			// без редиса запрос выполняется как обычно
+			logger.FormattedErrorWithAppInfo(gin2.GetAppInfo(c), "idempotency store error: "+err.Error())
+			c.Next()
+
+			return
+		}
+
+		if !isNew {
+			replayIdempotentResponse(c, redisClient, key, fingerprint)
+			c.Abort()
+
+			return
+		}
+
+		// ответ сохраняется и после отмены контекста запроса (клиент отключился, сработал таймаут)
+		storeCtx := context.WithoutCancel(ctx)
+		stored := false
+
+		// ключ освобождается при ошибке сервера и панике обработчика, чтобы клиент мог повторить запрос,
+		// а не ждать истечения LockTtl
+		defer func() {
+			if !stored {
+				redisClient.Del(storeCtx, key)
+			}
+		}()
+
+		writer := newBodyWriter(c, 0)
+		c.Next()
+		// ответ форматируется здесь, чтобы сохранить его и тогда, когда FormattedResponseMiddleware подключен раньше
+		helpers.FormattedResponse(c)
+
+		// ошибки сервера не сохраняются
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			return
+		}
+
+		record, _ := json.Marshal(idempotencyRecord{
+			State:       idempotencyCompleted,
+			Fingerprint: fingerprint,
+			StatusCode:  c.Writer.Status(),
+			ContentType: c.Writer.Header().Get("Content-Type"),
+			Body:        writer.body.Bytes(),
+		})
+
+		if err = redisClient.Set(storeCtx, key, record, cfg.Ttl).Err(); err != nil {
+			logger.FormattedErrorWithAppInfo(gin2.GetAppInfo(c), "idempotency store error: "+err.Error())
+
+			return
+		}
+
+		stored = true
+	}
+}
+
+// replayIdempotentResponse - ответ на повторный запрос с уже использованным ключом
+func replayIdempotentResponse(c *gin.Context, redisClient *redis.Client, key string, fingerprint string) {
+	val, err := redisClient.Get(c.Request.Context(), key).Bytes()
+
+	if errors.Is(err, redis.Nil) {
+		// первый запрос завершился ошибкой сервера или запись истекла между SetNX и Get
+		helpers.FormattedTextErrorResponse(c, http.StatusConflict, "request with this idempotency key is being processed, retry later", nil)
+
+		return
+	}
+
+	var record idempotencyRecord
+
+	if err == nil {
+		err = json.Unmarshal(val, &record)
+	}
+
+	if err != nil {
+		helpers.FormattedErrorResponse(c, http.StatusInternalServerError, err, nil)
+
+		return
+	}
+
+	if record.Fingerprint != fingerprint {
+		helpers.FormattedTextErrorResponse(c, http.StatusUnprocessableEntity, "idempotency key is already used with a different request", nil)
+
+		return
+	}
+
+	if record.State == idempotencyProcessing {
+		helpers.FormattedTextErrorResponse(c, http.StatusConflict, "request with this idempotency key is being processed", nil)
+
+		return
+	}
+
+	c.Header(constants.IdempotentReplayedHeaderName, strconv.FormatBool(true))
+	c.Data(record.StatusCode, record.ContentType, record.Body)
+}
+
+// requestFingerprint - хэш метода, пути и тела запроса, тело читается не больше maxBytes
+func requestFingerprint(c *gin.Context, maxBytes int) (string, error) {
+	bodyBytes, err := io.ReadAll(io.LimitReader(c.Request.Body, int64(maxBytes)+1))
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(bodyBytes) > maxBytes {
+		return "", errIdempotentBodyTooLarge
+	}
+
+	c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method + "\n" + c.Request.URL.RequestURI() + "\n"))
+	hash.Write(bodyBytes)
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// idempotencyScope - ключи разных пользователей и клиентов не пересекаются, анонимные запросы разделяются по IP
+func idempotencyScope(c *gin.Context) string {
+	appInfo := gin2.GetAppInfo(c)
+
+	if appInfo.ClientId != "" {
+		return "client:" + appInfo.ClientId
+	}
+
+	if appInfo.UserId != 0 {
+		return "user:" + strconv.Itoa(appInfo.UserId)
+	}
+
+	return "ip:" + c.ClientIP()
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,12 +1,11 @@
 package middleware
 
 import (
-	"github.com/ZhanibekTau/go-sdk/pkg/exception"
 	gin2 "github.com/ZhanibekTau/go-sdk/pkg/gin"
+	"github.com/ZhanibekTau/go-sdk/pkg/http/helpers"
 	"github.com/ZhanibekTau/go-sdk/pkg/logger"
 	"github.com/getsentry/sentry-go"
 	"github.com/gin-gonic/gin"
-	"github.com/mitchellh/mapstructure"
 )
 
 // LoggerMiddleware Middleware для логирования ответа и отправки ошибок в сентри
@@ -20,13 +19,14 @@ func LoggerMiddleware() gin.HandlerFunc {
 			logger.FormattedErrorWithAppInfo(appInfo, err.Error())
 		}
 
-		appExceptionObject, exists := c.Get("exception")
+		if appException, exists := helpers.GetAppException(c); exists {
+			// паника и ошибка, отправленная FormattedResponse, уже есть в сентри
+			if !helpers.IsExceptionCaptured(c) {
+				helpers.MarkExceptionCaptured(c)
+				sentry.CaptureException(appException)
+			}
 
-		if exists {
-			appException := exception.AppException{}
-			mapstructure.Decode(appExceptionObject, &appException)
-			sentry.CaptureException(appException.Error)
-			logger.FormattedErrorWithAppInfo(appInfo, appException.Error.Error())
+			logger.FormattedErrorWithAppInfo(appInfo, appException.Error())
 		}
 	}
 }
@@ -1,28 +1,122 @@
 package middleware
 
 import (
-	ratelimit "github.com/JGLTechnologies/gin-rate-limit"
+	"github.com/ZhanibekTau/go-sdk/pkg/constants"
+	gin2 "github.com/ZhanibekTau/go-sdk/pkg/gin"
+	"github.com/ZhanibekTau/go-sdk/pkg/http/helpers"
+	"github.com/ZhanibekTau/go-sdk/pkg/logger"
+	"github.com/ZhanibekTau/go-sdk/pkg/ratelimit"
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
-// RateLimiterMiddleware Middleware для rate limit
+// RateLimitKeyFunc - функция, возвращающая ключ, по которому считается лимит
+type RateLimitKeyFunc func(c *gin.Context) string
+
+// RateLimitByIp - лимит по IP клиента
+func RateLimitByIp(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// RateLimitByUser - лимит по пользователю из AppInfo, для анонимных запросов по IP
+func RateLimitByUser(c *gin.Context) string {
+	if userId := gin2.GetAppInfo(c).UserId; userId != 0 {
+		return "user:" + strconv.Itoa(userId)
+	}
+
+	return RateLimitByIp(c)
+}
+
+// RateLimitByClient - лимит по клиенту, аутентифицированному через ApiKeyMiddleware или HmacMiddleware, иначе по IP
+func RateLimitByClient(c *gin.Context) string {
+	if clientId := gin2.GetAppInfo(c).ClientId; clientId != "" {
+		return "client:" + clientId
+	}
+
+	return RateLimitByIp(c)
+}
+
+// RateLimitByRoute - лимит на маршрут целиком, для всех клиентов
+func RateLimitByRoute(c *gin.Context) string {
+	return "route:" + c.Request.Method + ":" + c.FullPath()
+}
+
+// RateLimitKeys - составной ключ, например лимит пользователя на каждый маршрут отдельно
+func RateLimitKeys(keyFuncs ...RateLimitKeyFunc) RateLimitKeyFunc {
+	return func(c *gin.Context) string {
+		keys := make([]string, len(keyFuncs))
+
+		for i, keyFunc := range keyFuncs {
+			keys[i] = keyFunc(c)
+		}
+
+		return strings.Join(keys, "|")
+	}
+}
+
+// RateLimitMiddleware Middleware для rate limit. Подключается на роутер или группу маршрутов со своим правилом.
+// При ошибке хранилища запрос пропускается, чтобы не терять лимит при недоступном редисе - ratelimit.NewFallbackStore.
+// Неверное правило (см. ratelimit.Rule.Validate) - ошибка
+func RateLimitMiddleware(store ratelimit.IStore, rule ratelimit.Rule, keyFunc RateLimitKeyFunc) (gin.HandlerFunc, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	if keyFunc == nil {
+		keyFunc = RateLimitByIp
+	}
+
+	return func(c *gin.Context) {
+		result, err := store.Take(c.Request.Context(), keyFunc(c), rule)
+
+		if err != nil {
+			logger.FormattedErrorWithAppInfo(gin2.GetAppInfo(c), "rate limit store error: "+err.Error())
+			c.Next()
+
+			return
+		}
+
+		c.Header(constants.RateLimitLimitHeaderName, strconv.Itoa(result.Limit))
+		c.Header(constants.RateLimitRemainingHeaderName, strconv.Itoa(result.Remaining))
+		c.Header(constants.RateLimitResetHeaderName, strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			c.Header(constants.RetryAfterHeaderName, strconv.Itoa(retryAfter))
+			helpers.FormattedTextErrorResponse(c, http.StatusTooManyRequests, "Too many requests. Try again in "+result.RetryAfter.Round(time.Second).String(), map[string]any{
+				"limit":       result.Limit,
+				"retry_after": retryAfter,
+			})
+			c.Abort()
+
+			return
+		}
+
+		c.Next()
+	}, nil
+}
+
+// RateLimiterMiddleware Middleware для rate limit: limit запросов в секунду с одного IP, 0 - без ограничения.
+// При недоступном редисе лимит считается в памяти процесса
 func RateLimiterMiddleware(redis *redis.Client, limit uint) gin.HandlerFunc {
-	// This makes it so each ip can only make n requests per second
-	store := ratelimit.RedisStore(&ratelimit.RedisOptions{
-		RedisClient: redis,
-		Rate:        time.Second,
-		Limit:       limit,
-	})
-
-	return ratelimit.RateLimiter(store, &ratelimit.Options{
-		ErrorHandler: func(context *gin.Context, info ratelimit.Info) {
-			context.String(http.StatusTooManyRequests, "Too many requests. Try again in "+time.Until(info.ResetTime).String())
-		},
-		KeyFunc: func(context *gin.Context) string {
-			return context.ClientIP()
-		},
-	})
+	if limit == 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	store := ratelimit.NewFallbackStore(ratelimit.NewRedisStore(redis), ratelimit.NewMemoryStore())
+	// правило PerSecond с положительным лимитом всегда валидно
+	handler, _ := RateLimitMiddleware(store, ratelimit.PerSecond(int(limit), ratelimit.FixedWindow), RateLimitByIp)
+
+	return handler
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
 }
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"github.com/ZhanibekTau/go-sdk/pkg/config"
+	"github.com/ZhanibekTau/go-sdk/pkg/constants"
 	gin2 "github.com/ZhanibekTau/go-sdk/pkg/gin"
 	"github.com/gin-gonic/gin"
 )
@@ -10,6 +11,10 @@ import (
 func RequestMiddleware(baseConfig *config.BaseConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		gin2.SetAppInfo(c, baseConfig)
+		appInfo := gin2.GetAppInfo(c)
+		// данные запроса доступны слоям, которые принимают только context.Context
+		c.Request = c.Request.WithContext(config.WithAppInfo(c.Request.Context(), appInfo))
+		c.Header(constants.RequestIdHeaderName, appInfo.RequestId)
 		c.Next()
 	}
 }
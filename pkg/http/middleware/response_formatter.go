@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"github.com/ZhanibekTau/go-sdk/pkg/http/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+// ResponseFormatterMiddleware Middleware, задающий формат ответа для роутера или группы маршрутов,
+// например helpers.NewProblemResponseFormatter для публичного API
+func ResponseFormatterMiddleware(formatter helpers.ResponseFormatter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		helpers.SetResponseFormatter(c, formatter)
+		c.Next()
+	}
+}
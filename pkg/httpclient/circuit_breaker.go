@@ -0,0 +1,198 @@
+package httpclient
+
+import (
+	"errors"
+	"github.com/ZhanibekTau/go-sdk/pkg/logger"
+	"github.com/ZhanibekTau/go-sdk/pkg/metrics"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen - circuit breaker хоста открыт, запрос не отправлялся
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+const (
+	StateClosed   string = "closed"
+	StateHalfOpen string = "half_open"
+	StateOpen     string = "open"
+)
+
+var (
+	breakerMetricsOnce sync.Once
+	breakerState       *metrics.Gauge
+	breakerTransitions *metrics.Counter
+)
+
+// registerBreakerMetrics - регистрирует метрики circuit breaker один раз при создании первого клиента.
+// Если метрики не зарегистрировались, ошибка пишется в лог и breaker работает без метрик
+func registerBreakerMetrics() {
+	breakerMetricsOnce.Do(func() {
+		state, err := metrics.NewGauge("http_client_circuit_breaker_state", "Circuit breaker state by host: 0 - closed, 1 - half open, 2 - open", "host")
+
+		if err != nil {
+			logger.Error("circuit breaker metrics: %s", err.Error())
+
+			return
+		}
+
+		transitions, err := metrics.NewCounter("http_client_circuit_breaker_transitions_total", "Circuit breaker state transitions by host", "host", "from", "to")
+
+		if err != nil {
+			logger.Error("circuit breaker metrics: %s", err.Error())
+
+			return
+		}
+
+		breakerState, breakerTransitions = state, transitions
+	})
+}
+
+// CircuitBreakerConfig - настройки circuit breaker
+type CircuitBreakerConfig struct {
+	// FailureThreshold - сколько ошибок подряд открывают breaker, по умолчанию 5
+	FailureThreshold int
+	// OpenTimeout - сколько breaker открыт до пробных запросов, по умолчанию 30s
+	OpenTimeout time.Duration
+	// HalfOpenRequests - сколько пробных запросов пропускается в полуоткрытом состоянии, по умолчанию 1.
+	// Если все они успешны, breaker закрывается, при первой ошибке снова открывается
+	HalfOpenRequests int
+}
+
+func newCircuitBreaker(host string, cfg CircuitBreakerConfig) *circuitBreaker {
+	if cfg.FailureThreshold == 0 {
+		cfg.FailureThreshold = 5
+	}
+
+	if cfg.OpenTimeout == 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+
+	if cfg.HalfOpenRequests == 0 {
+		cfg.HalfOpenRequests = 1
+	}
+
+	if breakerState != nil {
+		breakerState.Set(0, host)
+	}
+
+	return &circuitBreaker{host: host, cfg: cfg, state: StateClosed}
+}
+
+// circuitBreaker - circuit breaker хоста
+type circuitBreaker struct {
+	mu        sync.Mutex
+	host      string
+	cfg       CircuitBreakerConfig
+	state     string
+	failures  int
+	openedAt  time.Time
+	inFlight  int
+	successes int
+	// generation - меняется при каждой смене состояния, чтобы не учитывать результаты запросов из прошлого состояния
+	generation int
+}
+
+// allow - можно ли отправить запрос, возвращает поколение для report
+func (b *circuitBreaker) allow() (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen {
+		if time.Since(b.openedAt) < b.cfg.OpenTimeout {
+			return 0, ErrCircuitOpen
+		}
+
+		b.setState(StateHalfOpen)
+	}
+
+	if b.state == StateHalfOpen {
+		if b.inFlight >= b.cfg.HalfOpenRequests {
+			return 0, ErrCircuitOpen
+		}
+
+		b.inFlight++
+	}
+
+	return b.generation, nil
+}
+
+// release - запрос, пропущенный allow, завершился без результата (например, отменен вызывающим кодом):
+// не считается ни успехом, ни ошибкой, освобождает место пробного запроса
+func (b *circuitBreaker) release(generation int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation == b.generation && b.state == StateHalfOpen {
+		b.inFlight--
+	}
+}
+
+// report - результат запроса, пропущенного allow
+func (b *circuitBreaker) report(generation int, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation != b.generation {
+		return
+	}
+
+	switch b.state {
+	case StateClosed:
+		if success {
+			b.failures = 0
+
+			return
+		}
+
+		b.failures++
+
+		if b.failures >= b.cfg.FailureThreshold {
+			b.setState(StateOpen)
+		}
+	case StateHalfOpen:
+		b.inFlight--
+
+		if !success {
+			b.setState(StateOpen)
+
+			return
+		}
+
+		b.successes++
+
+		if b.successes >= b.cfg.HalfOpenRequests {
+			b.setState(StateClosed)
+		}
+	}
+}
+
+func (b *circuitBreaker) setState(state string) {
+	from := b.state
+	b.state = state
+	b.failures = 0
+	b.successes = 0
+	b.inFlight = 0
+	b.generation++
+
+	if state == StateOpen {
+		b.openedAt = time.Now()
+	}
+
+	if breakerState != nil {
+		breakerState.Set(stateValue(state), b.host)
+		breakerTransitions.Inc(b.host, from, state)
+	}
+
+	logger.Info("circuit breaker %s: %s -> %s", b.host, from, state)
+}
+
+func stateValue(state string) float64 {
+	switch state {
+	case StateHalfOpen:
+		return 1
+	case StateOpen:
+		return 2
+	}
+
+	return 0
+}
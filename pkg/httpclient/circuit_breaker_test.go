@@ -0,0 +1,176 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// breakerServer - сервер, отвечающий статусом из status
+func breakerServer(t *testing.T, hits *atomic.Int32, status *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(int(status.Load()))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func currentState(t *testing.T, client *Client, server *httptest.Server) string {
+	t.Helper()
+
+	serverUrl, _ := url.Parse(server.URL)
+	breaker := client.breakers[serverUrl.Host]
+
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	return breaker.state
+}
+
+func TestCircuitBreakerOpensAfterFailures(t *testing.T) {
+	var hits, status atomic.Int32
+	status.Store(http.StatusInternalServerError)
+	server := breakerServer(t, &hits, &status)
+	client := NewClient(server.URL).SetPolicy(Policy{
+		Breaker: &CircuitBreakerConfig{FailureThreshold: 3, OpenTimeout: time.Hour},
+	})
+
+	for range 3 {
+		if _, err := client.Get(context.Background(), "/").Do(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if state := currentState(t, client, server); state != StateOpen {
+		t.Fatalf("state %s after 3 failures, want open", state)
+	}
+
+	if _, err := client.Get(context.Background(), "/").Do(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("error %v, want ErrCircuitOpen", err)
+	}
+
+	if hits.Load() != 3 {
+		t.Fatalf("%d requests reached server, want 3", hits.Load())
+	}
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	var hits, status atomic.Int32
+	status.Store(http.StatusNotFound)
+	server := breakerServer(t, &hits, &status)
+	client := NewClient(server.URL).SetPolicy(Policy{
+		Breaker: &CircuitBreakerConfig{FailureThreshold: 2},
+	})
+
+	for range 5 {
+		_, _ = client.Get(context.Background(), "/").Do()
+	}
+
+	if state := currentState(t, client, server); state != StateClosed {
+		t.Fatalf("state %s after 4xx responses, want closed", state)
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	var hits, status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	server := breakerServer(t, &hits, &status)
+	client := NewClient(server.URL).SetPolicy(Policy{
+		Breaker: &CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: 50 * time.Millisecond, HalfOpenRequests: 2},
+	})
+
+	_, _ = client.Get(context.Background(), "/").Do()
+
+	if state := currentState(t, client, server); state != StateOpen {
+		t.Fatalf("state %s after failure, want open", state)
+	}
+
+	// пробный запрос с ошибкой снова открывает breaker
+	time.Sleep(60 * time.Millisecond)
+	_, _ = client.Get(context.Background(), "/").Do()
+
+	if state := currentState(t, client, server); state != StateOpen {
+		t.Fatalf("state %s after failed probe, want open", state)
+	}
+
+	if _, err := client.Get(context.Background(), "/").Do(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("error %v right after failed probe, want ErrCircuitOpen", err)
+	}
+
+	// breaker закрывается, только когда успешны все HalfOpenRequests пробных запросов
+	time.Sleep(60 * time.Millisecond)
+	status.Store(http.StatusOK)
+	_, _ = client.Get(context.Background(), "/").Do()
+
+	if state := currentState(t, client, server); state != StateHalfOpen {
+		t.Fatalf("state %s after first successful probe, want half_open", state)
+	}
+
+	_, _ = client.Get(context.Background(), "/").Do()
+
+	if state := currentState(t, client, server); state != StateClosed {
+		t.Fatalf("state %s after successful probes, want closed", state)
+	}
+}
+
+func TestCircuitBreakerIgnoresCallerCancel(t *testing.T) {
+	// сервер не отвечает, запрос завершается по дедлайну вызывающего кода
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(server.URL).SetPolicy(Policy{
+		Breaker: &CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Hour},
+	})
+
+	for range 3 {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		_, err := client.Get(ctx, "/").Do()
+		cancel()
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("error %v, want context.DeadlineExceeded", err)
+		}
+	}
+
+	if state := currentState(t, client, server); state != StateClosed {
+		t.Fatalf("state %s after caller timeouts, want closed", state)
+	}
+}
+
+func TestCircuitBreakerLimitsHalfOpenRequests(t *testing.T) {
+	breaker := newCircuitBreaker("limits.test", CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Millisecond})
+	generation, _ := breaker.allow()
+	breaker.report(generation, false)
+	time.Sleep(2 * time.Millisecond)
+
+	if _, err := breaker.allow(); err != nil {
+		t.Fatalf("probe denied: %v", err)
+	}
+
+	if _, err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second concurrent probe: error %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresStaleReports(t *testing.T) {
+	breaker := newCircuitBreaker("stale.test", CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Hour})
+	stale, _ := breaker.allow()
+	current, _ := breaker.allow()
+	breaker.report(current, false)
+	// ответ на запрос, отправленный до открытия breaker, не меняет состояние
+	breaker.report(stale, true)
+
+	if breaker.state != StateOpen {
+		t.Fatalf("state %s after stale success, want open", breaker.state)
+	}
+}
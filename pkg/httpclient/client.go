@@ -0,0 +1,134 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NewClient - HTTP клиент для вызова других сервисов. baseUrl подставляется перед путем каждого запроса.
+// Создается после metrics.InitMetrics, чтобы метрики circuit breaker получили namespace сервиса
+func NewClient(baseUrl string) *Client {
+	registerBreakerMetrics()
+
+	return &Client{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		baseUrl:    strings.TrimRight(baseUrl, "/"),
+		headers:    http.Header{},
+		policies:   map[string]Policy{},
+		breakers:   map[string]*circuitBreaker{},
+	}
+}
+
+// Client - HTTP клиент с трассировкой и пробросом заголовков AppInfo
+type Client struct {
+	httpClient *http.Client
+	baseUrl    string
+	headers    http.Header
+	policy     Policy
+	policies   map[string]Policy
+	mu         sync.Mutex
+	breakers   map[string]*circuitBreaker
+}
+
+// SetTimeout - общий таймаут запроса
+func (c *Client) SetTimeout(timeout time.Duration) *Client {
+	c.httpClient.Timeout = timeout
+
+	return c
+}
+
+// SetTransport - транспорт клиента, например auth.HmacSigner.Transport для подписи запросов
+func (c *Client) SetTransport(transport http.RoundTripper) *Client {
+	c.httpClient.Transport = transport
+
+	return c
+}
+
+// SetPolicy - политика повторов и circuit breaker для всех хостов, у которых нет своей политики
+func (c *Client) SetPolicy(policy Policy) *Client {
+	c.policy = policy
+
+	return c
+}
+
+// SetHostPolicy - политика повторов и circuit breaker для хоста (host или host:port из url запроса)
+func (c *Client) SetHostPolicy(host string, policy Policy) *Client {
+	c.policies[host] = policy
+
+	return c
+}
+
+// policyFor - политика хоста
+func (c *Client) policyFor(host string) Policy {
+	if policy, ok := c.policies[host]; ok {
+		return policy
+	}
+
+	return c.policy
+}
+
+// breakerFor - circuit breaker хоста, nil если в политике его нет
+func (c *Client) breakerFor(host string, policy Policy) *circuitBreaker {
+	if policy.Breaker == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	breaker, ok := c.breakers[host]
+
+	if !ok {
+		breaker = newCircuitBreaker(host, *policy.Breaker)
+		c.breakers[host] = breaker
+	}
+
+	return breaker
+}
+
+// SetHeader - заголовок, который отправляется с каждым запросом
+func (c *Client) SetHeader(key string, value string) *Client {
+	c.headers.Set(key, value)
+
+	return c
+}
+
+// Request - новый запрос
+func (c *Client) Request(ctx context.Context, method string, path string) *RequestBuilder {
+	return &RequestBuilder{
+		client:  c,
+		ctx:     ctx,
+		method:  method,
+		url:     c.baseUrl + path,
+		headers: c.headers.Clone(),
+		query:   map[string][]string{},
+	}
+}
+
+// Get - новый GET запрос
+func (c *Client) Get(ctx context.Context, path string) *RequestBuilder {
+	return c.Request(ctx, http.MethodGet, path)
+}
+
+// Post - новый POST запрос
+func (c *Client) Post(ctx context.Context, path string) *RequestBuilder {
+	return c.Request(ctx, http.MethodPost, path)
+}
+
+// Put - новый PUT запрос
+func (c *Client) Put(ctx context.Context, path string) *RequestBuilder {
+	return c.Request(ctx, http.MethodPut, path)
+}
+
+// Patch - новый PATCH запрос
+func (c *Client) Patch(ctx context.Context, path string) *RequestBuilder {
+	return c.Request(ctx, http.MethodPatch, path)
+}
+
+// Delete - новый DELETE запрос
+func (c *Client) Delete(ctx context.Context, path string) *RequestBuilder {
+	return c.Request(ctx, http.MethodDelete, path)
+}
@@ -0,0 +1,245 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/ZhanibekTau/go-sdk/pkg/config"
+	"github.com/ZhanibekTau/go-sdk/pkg/constants"
+	"github.com/ZhanibekTau/go-sdk/pkg/tracer"
+	span2 "github.com/ZhanibekTau/go-sdk/pkg/tracer/span"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// RequestBuilder - построитель запроса
+type RequestBuilder struct {
+	client      *Client
+	ctx         context.Context
+	method      string
+	url         string
+	headers     http.Header
+	query       url.Values
+	body        []byte
+	contentType string
+	err         error
+}
+
+// Header - заголовок запроса
+func (b *RequestBuilder) Header(key string, value string) *RequestBuilder {
+	b.headers.Set(key, value)
+
+	return b
+}
+
+// Query - query параметр запроса
+func (b *RequestBuilder) Query(key string, value string) *RequestBuilder {
+	b.query.Add(key, value)
+
+	return b
+}
+
+// QueryParams - query параметры запроса
+func (b *RequestBuilder) QueryParams(params map[string]string) *RequestBuilder {
+	for key, value := range params {
+		b.query.Add(key, value)
+	}
+
+	return b
+}
+
+// JsonBody - тело запроса в JSON
+func (b *RequestBuilder) JsonBody(body any) *RequestBuilder {
+	b.body, b.err = json.Marshal(body)
+	b.contentType = "application/json"
+
+	return b
+}
+
+// Body - тело запроса как есть
+func (b *RequestBuilder) Body(body []byte, contentType string) *RequestBuilder {
+	b.body = body
+	b.contentType = contentType
+
+	return b
+}
+
+// AppInfo - пробрасывает в запрос Request-Id, язык, город и пользователя текущего запроса.
+// Если не вызван, данные берутся из контекста запроса (см. config.WithAppInfo)
+func (b *RequestBuilder) AppInfo(appInfo *config.AppInfo) *RequestBuilder {
+	setAppInfoHeaders(b.headers, appInfo, true)
+
+	return b
+}
+
+// setAppInfoHeaders - заголовки с данными запроса, overwrite false - уже заданные заголовки не меняются
+func setAppInfoHeaders(headers http.Header, appInfo *config.AppInfo, overwrite bool) {
+	if appInfo == nil {
+		return
+	}
+
+	set := func(key string, value string) {
+		if overwrite || headers.Get(key) == "" {
+			headers.Set(key, value)
+		}
+	}
+
+	if appInfo.RequestId != "" {
+		set(constants.RequestIdHeaderName, appInfo.RequestId)
+	}
+
+	if appInfo.LanguageCode != "" {
+		set(constants.LanguageHeaderName, appInfo.LanguageCode)
+	}
+
+	if appInfo.CityId != 0 {
+		set(constants.CityHeaderName, strconv.Itoa(appInfo.CityId))
+	}
+
+	if appInfo.UserId != 0 {
+		set(constants.UserHeaderName, strconv.Itoa(appInfo.UserId))
+	}
+}
+
+// Do - выполняет запрос с учетом политики хоста: повторы и circuit breaker.
+// Ответ с любым статусом возвращается без ошибки, проверку статуса делают Decode и DecodeData
+func (b *RequestBuilder) Do() (*Response, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	ctx := b.ctx
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	requestUrl := b.url
+
+	if len(b.query) > 0 {
+		requestUrl += "?" + b.query.Encode()
+	}
+
+	parsedUrl, err := url.Parse(requestUrl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	policy := b.client.policyFor(parsedUrl.Host)
+	breaker := b.client.breakerFor(parsedUrl.Host, policy)
+	retry := policy.Retry
+
+	if retry != nil {
+		retry.Budget.onRequest()
+	}
+
+	for attempt := 1; ; attempt++ {
+		generation := 0
+
+		if breaker != nil {
+			if generation, err = breaker.allow(); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := b.doOnce(ctx, requestUrl, retry)
+
+		if breaker != nil && err != nil && ctx.Err() != nil {
+			// запрос отменен вызывающим кодом или истек его дедлайн, о вызываемом сервисе это ничего не говорит
+			breaker.release(generation)
+		} else if breaker != nil {
+			// ошибки клиента (4xx) не говорят о проблемах вызываемого сервиса
+			breaker.report(generation, err == nil && resp.StatusCode < http.StatusInternalServerError)
+		}
+
+		if attempt >= retry.maxAttempts() || ctx.Err() != nil || !retry.shouldRetry(b.method, resp) || !retry.Budget.tryRetry() {
+			return resp, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return resp, err
+		case <-time.After(retry.delay(attempt, resp)):
+		}
+	}
+}
+
+// doOnce - одна попытка запроса
+func (b *RequestBuilder) doOnce(ctx context.Context, requestUrl string, retry *RetryPolicy) (*Response, error) {
+	if retry != nil && retry.AttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, retry.AttemptTimeout)
+		defer cancel()
+	}
+
+	// клиентский спан, trace id передается вызываемому сервису в B3 заголовках
+	var span trace.Span
+
+	if tracer.TraceClient != nil && tracer.TraceClient.IsEnabled {
+		ctx, span = tracer.TraceClient.CreateSpan(ctx, "[HTTP "+b.method+"] "+b.url, trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End()
+
+		span.SetAttributes(
+			attribute.String(span2.AttributeHttpMethod, b.method),
+			attribute.String(span2.AttributeHttpUrl, requestUrl),
+		)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, b.method, requestUrl, bytes.NewReader(b.body))
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header = b.headers.Clone()
+	setAppInfoHeaders(req.Header, config.AppInfoFromContext(ctx), false)
+
+	if b.contentType != "" {
+		req.Header.Set("Content-Type", b.contentType)
+	}
+
+	if span != nil {
+		tracer.TraceClient.InjectHttpTraceId(ctx, req)
+	}
+
+	resp, err := b.client.httpClient.Do(req)
+
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if span != nil {
+		span.SetAttributes(attribute.Int(span2.AttributeRespHttpCode, resp.StatusCode))
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+		Url:        requestUrl,
+	}, nil
+}
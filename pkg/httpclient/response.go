@@ -0,0 +1,114 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/ZhanibekTau/go-sdk/pkg/exception"
+	"net/http"
+)
+
+// Response - ответ вызываемого сервиса
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Url        string
+}
+
+// IsSuccess - статус ответа 2xx
+func (r *Response) IsSuccess() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// envelope - стандартный формат ответа сервисов (см. helpers.FormattedResponse)
+type envelope struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// errorData - данные ошибки в стандартном формате ответа
+type errorData struct {
+	Status      int            `json:"status"`
+	Error       string         `json:"error"`
+	Message     string         `json:"message"`
+	RequestId   string         `json:"request_id"`
+	Hostname    string         `json:"hostname"`
+	ServiceCode int            `json:"service_code"`
+	Details     map[string]any `json:"details"`
+}
+
+// AppException - ошибка из ответа со статусом не 2xx. Если ответ в стандартном формате,
+// сохраняются сообщение, service_code и детали вызываемого сервиса
+func (r *Response) AppException() *exception.AppException {
+	context := map[string]any{"url": r.Url}
+	message := http.StatusText(r.StatusCode)
+
+	var body envelope
+	var data errorData
+
+	if json.Unmarshal(r.Body, &body) == nil && json.Unmarshal(body.Data, &data) == nil && data.Message != "" {
+		appException := exception.NewAppException(r.StatusCode, errors.New(data.Message), context)
+		appException.ServiceCode = data.ServiceCode
+		appException.ErrorType = data.Error
+		context["hostname"] = data.Hostname
+		context["details"] = data.Details
+
+		return appException
+	}
+
+	if len(r.Body) > 0 {
+		context["body"] = string(r.Body)
+	}
+
+	return exception.NewAppException(r.StatusCode, errors.New(message), context)
+}
+
+// Decode - выполняет запрос и декодирует JSON ответ в T. Ответ со статусом не 2xx возвращается как AppException
+func Decode[T any](b *RequestBuilder) (*T, *exception.AppException) {
+	resp, err := b.Do()
+
+	if errors.Is(err, ErrCircuitOpen) {
+		return nil, exception.NewAppException(http.StatusServiceUnavailable, err, map[string]any{"url": b.url})
+	}
+
+	if err != nil {
+		return nil, exception.NewAppException(http.StatusBadGateway, err, map[string]any{"url": b.url})
+	}
+
+	if !resp.IsSuccess() {
+		return nil, resp.AppException()
+	}
+
+	var result T
+
+	if len(resp.Body) == 0 {
+		return &result, nil
+	}
+
+	if err = json.Unmarshal(resp.Body, &result); err != nil {
+		return nil, exception.NewInternalServerAppException(err, map[string]any{"url": resp.Url})
+	}
+
+	return &result, nil
+}
+
+// DecodeData - выполняет запрос и декодирует поле data стандартного формата ответа в T
+func DecodeData[T any](b *RequestBuilder) (*T, *exception.AppException) {
+	body, appException := Decode[envelope](b)
+
+	if appException != nil {
+		return nil, appException
+	}
+
+	var result T
+
+	if len(body.Data) == 0 {
+		return &result, nil
+	}
+
+	if err := json.Unmarshal(body.Data, &result); err != nil {
+		return nil, exception.NewInternalServerAppException(err, map[string]any{"url": b.url})
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,200 @@
+package httpclient
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Policy - политика вызовов хоста: повторы и circuit breaker. Любую часть можно не задавать
+type Policy struct {
+	Retry   *RetryPolicy
+	Breaker *CircuitBreakerConfig
+}
+
+// RetryPolicy - политика повторов запроса
+type RetryPolicy struct {
+	// MaxAttempts - максимум попыток, включая первую
+	MaxAttempts int
+	// BaseDelay - задержка перед первым повтором, дальше растет экспоненциально, по умолчанию 100ms
+	BaseDelay time.Duration
+	// MaxDelay - максимальная задержка между попытками, по умолчанию 2s
+	MaxDelay time.Duration
+	// AttemptTimeout - таймаут одной попытки, 0 - без отдельного таймаута
+	AttemptTimeout time.Duration
+	// Statuses - статусы, при которых повторяются идемпотентные запросы, по умолчанию 502, 503, 504
+	Statuses []int
+	// NonIdempotentStatuses - статусы, при которых повторяются и неидемпотентные запросы (POST, PATCH),
+	// например 429 или 503, если вызываемый сервис гарантирует, что запрос не обработан
+	NonIdempotentStatuses []int
+	// Budget - бюджет повторов, общий для всех запросов к хосту
+	Budget *RetryBudget
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+
+	return p.MaxAttempts
+}
+
+// shouldRetry - нужно ли повторять запрос после попытки. resp nil, если попытка завершилась ошибкой транспорта
+func (p *RetryPolicy) shouldRetry(method string, resp *Response) bool {
+	if p == nil {
+		return false
+	}
+
+	idempotent := isIdempotent(method)
+
+	if resp == nil {
+		// неидемпотентный запрос мог дойти до сервиса, повторять его небезопасно
+		return idempotent
+	}
+
+	statuses := p.Statuses
+
+	if statuses == nil {
+		statuses = []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+	}
+
+	if idempotent && containsInt(statuses, resp.StatusCode) {
+		return true
+	}
+
+	return containsInt(p.NonIdempotentStatuses, resp.StatusCode)
+}
+
+// delay - экспоненциальная задержка перед повтором с full jitter, Retry-After ответа имеет приоритет
+func (p *RetryPolicy) delay(attempt int, resp *Response) time.Duration {
+	baseDelay := p.BaseDelay
+
+	if baseDelay == 0 {
+		baseDelay = 100 * time.Millisecond
+	}
+
+	maxDelay := p.MaxDelay
+
+	if maxDelay == 0 {
+		maxDelay = 2 * time.Second
+	}
+
+	if resp != nil {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return min(retryAfter, maxDelay)
+		}
+	}
+
+	// удваивается до MaxDelay, без сдвига, который переполняется на большом числе попыток
+	backoff := baseDelay
+
+	for i := 1; i < attempt && backoff < maxDelay; i++ {
+		backoff *= 2
+	}
+
+	if backoff > maxDelay {
+		backoff = maxDelay
+	}
+
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
+
+// parseRetryAfter - Retry-After в секундах или HTTP датой, дата в прошлом - без задержки
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+// NewRetryBudget - бюджет повторов: за окно в 10 секунд допускается minRetries повторов плюс ratio от числа запросов.
+// Не дает повторам умножить нагрузку на сервис, который и так не справляется
+func NewRetryBudget(ratio float64, minRetries int) *RetryBudget {
+	return &RetryBudget{
+		ratio:      ratio,
+		minRetries: minRetries,
+		window:     10 * time.Second,
+	}
+}
+
+// RetryBudget - бюджет повторов
+type RetryBudget struct {
+	mu          sync.Mutex
+	ratio       float64
+	minRetries  int
+	window      time.Duration
+	windowStart time.Time
+	requests    int
+	retries     int
+}
+
+// onRequest - учитывает первую попытку запроса
+func (b *RetryBudget) onRequest() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rotate()
+	b.requests++
+}
+
+// tryRetry - списывает повтор из бюджета, false если бюджет исчерпан
+func (b *RetryBudget) tryRetry() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rotate()
+
+	if float64(b.retries) >= float64(b.minRetries)+b.ratio*float64(b.requests) {
+		return false
+	}
+
+	b.retries++
+
+	return true
+}
+
+func (b *RetryBudget) rotate() {
+	if now := time.Now(); now.Sub(b.windowStart) > b.window {
+		b.windowStart = now
+		b.requests = 0
+		b.retries = 0
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,218 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// statusServer - сервер, отвечающий статусами по порядку, последний статус повторяется
+func statusServer(t *testing.T, hits *atomic.Int32, statuses ...int) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit := int(hits.Add(1))
+		status := statuses[min(hit, len(statuses))-1]
+
+		if status == http.StatusServiceUnavailable && r.Header.Get("X-Retry-After") != "" {
+			w.Header().Set("Retry-After", r.Header.Get("X-Retry-After"))
+		}
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestRetryUntilSuccess(t *testing.T) {
+	var hits atomic.Int32
+	server := statusServer(t, &hits, http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK)
+	client := NewClient(server.URL).SetPolicy(Policy{
+		Retry: &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond},
+	})
+
+	resp, err := client.Get(context.Background(), "/").Do()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != http.StatusOK || hits.Load() != 3 {
+		t.Fatalf("status %d after %d attempts, want 200 after 3", resp.StatusCode, hits.Load())
+	}
+}
+
+func TestRetryStopsAtMaxAttempts(t *testing.T) {
+	var hits atomic.Int32
+	server := statusServer(t, &hits, http.StatusServiceUnavailable)
+	client := NewClient(server.URL).SetPolicy(Policy{
+		Retry: &RetryPolicy{MaxAttempts: 4, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+	})
+
+	resp, err := client.Get(context.Background(), "/").Do()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != http.StatusServiceUnavailable || hits.Load() != 4 {
+		t.Fatalf("status %d after %d attempts, want 503 after 4", resp.StatusCode, hits.Load())
+	}
+}
+
+func TestRetrySkipsNonIdempotentRequests(t *testing.T) {
+	var hits atomic.Int32
+	server := statusServer(t, &hits, http.StatusServiceUnavailable, http.StatusOK)
+	policy := &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	client := NewClient(server.URL).SetPolicy(Policy{Retry: policy})
+
+	if resp, _ := client.Post(context.Background(), "/").Do(); resp.StatusCode != http.StatusServiceUnavailable || hits.Load() != 1 {
+		t.Fatalf("POST: status %d after %d attempts, want 503 after 1", resp.StatusCode, hits.Load())
+	}
+
+	hits.Store(0)
+	policy.NonIdempotentStatuses = []int{http.StatusServiceUnavailable}
+
+	if resp, _ := client.Post(context.Background(), "/").Do(); resp.StatusCode != http.StatusOK || hits.Load() != 2 {
+		t.Fatalf("POST with NonIdempotentStatuses: status %d after %d attempts, want 200 after 2", resp.StatusCode, hits.Load())
+	}
+}
+
+func TestRetryDelayBackoff(t *testing.T) {
+	policy := &RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+	limits := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond, 50 * time.Millisecond}
+
+	for i, limit := range limits {
+		for range 100 {
+			if delay := policy.delay(i+1, nil); delay < 0 || delay > limit {
+				t.Fatalf("attempt %d: delay %s, want within [0, %s]", i+1, delay, limit)
+			}
+		}
+	}
+
+	// сдвиг на большое число попыток не должен переполняться
+	if delay := policy.delay(100, nil); delay < 0 || delay > policy.MaxDelay {
+		t.Fatalf("attempt 100: delay %s, want within [0, %s]", delay, policy.MaxDelay)
+	}
+}
+
+func TestRetryHonoursRetryAfter(t *testing.T) {
+	policy := &RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: 5 * time.Second}
+	resp := &Response{Header: http.Header{"Retry-After": []string{"2"}}}
+
+	if delay := policy.delay(1, resp); delay != 2*time.Second {
+		t.Fatalf("delay %s, want Retry-After 2s", delay)
+	}
+
+	policy.MaxDelay = time.Second
+
+	if delay := policy.delay(1, resp); delay != time.Second {
+		t.Fatalf("delay %s, want Retry-After capped by MaxDelay 1s", delay)
+	}
+
+	// Retry-After HTTP датой
+	resp.Header.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+
+	if delay := policy.delay(1, resp); delay != time.Second {
+		t.Fatalf("delay %s, want Retry-After date capped by MaxDelay 1s", delay)
+	}
+
+	resp.Header.Set("Retry-After", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+
+	if delay := policy.delay(1, resp); delay != 0 {
+		t.Fatalf("delay %s, want 0 for Retry-After date in the past", delay)
+	}
+
+	var hits atomic.Int32
+	server := statusServer(t, &hits, http.StatusServiceUnavailable, http.StatusOK)
+	client := NewClient(server.URL).SetPolicy(Policy{
+		Retry: &RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Second},
+	})
+
+	start := time.Now()
+	resp, err := client.Get(context.Background(), "/").Header("X-Retry-After", "1").Do()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); resp.StatusCode != http.StatusOK || elapsed < time.Second {
+		t.Fatalf("status %d after %s, want 200 after Retry-After 1s", resp.StatusCode, elapsed)
+	}
+}
+
+func TestRetryStopsOnContextCancel(t *testing.T) {
+	var hits atomic.Int32
+	server := statusServer(t, &hits, http.StatusServiceUnavailable)
+	client := NewClient(server.URL).SetPolicy(Policy{
+		Retry: &RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: time.Second},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _ = client.Get(ctx, "/").Header("X-Retry-After", "1").Do()
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond || hits.Load() != 1 {
+		t.Fatalf("%d attempts in %s, want 1 attempt stopped by context", hits.Load(), elapsed)
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	var hits atomic.Int32
+	server := statusServer(t, &hits, http.StatusServiceUnavailable)
+	client := NewClient(server.URL).SetPolicy(Policy{
+		Retry: &RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+			MaxDelay:    time.Millisecond,
+			// без доли от запросов бюджет - ровно один повтор за окно
+			Budget: NewRetryBudget(0, 1),
+		},
+	})
+
+	_, _ = client.Get(context.Background(), "/").Do()
+
+	if hits.Load() != 2 {
+		t.Fatalf("first request: %d attempts, want 2 (one retry from budget)", hits.Load())
+	}
+
+	hits.Store(0)
+	_, _ = client.Get(context.Background(), "/").Do()
+
+	if hits.Load() != 1 {
+		t.Fatalf("second request: %d attempts, want 1 (budget exhausted)", hits.Load())
+	}
+}
+
+func TestRetryBudgetRatio(t *testing.T) {
+	budget := NewRetryBudget(0.5, 0)
+
+	for range 4 {
+		budget.onRequest()
+	}
+
+	retries := 0
+
+	for budget.tryRetry() {
+		retries++
+	}
+
+	if retries != 2 {
+		t.Fatalf("%d retries for 4 requests with ratio 0.5, want 2", retries)
+	}
+
+	// новое окно обнуляет бюджет
+	budget.windowStart = time.Now().Add(-budget.window - time.Second)
+	budget.onRequest()
+	budget.onRequest()
+
+	if !budget.tryRetry() {
+		t.Fatal("retry denied in a new window")
+	}
+}
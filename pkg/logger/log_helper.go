@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"github.com/ZhanibekTau/go-sdk/pkg/config"
 	"github.com/ZhanibekTau/go-sdk/pkg/exception"
 	"log"
@@ -13,13 +14,13 @@ import (
 // Info Обычный лог
 func Info(format string, v ...any) {
 	infoLog := log.New(os.Stdout, "INFO\t", log.Ldate|log.Ltime)
-	infoLog.Printf(format, v)
+	infoLog.Printf(format, v...)
 }
 
 // Error лог с ошибкой
 func Error(format string, v ...any) {
 	infoLog := log.New(os.Stdout, "ERROR\t", log.Ldate|log.Ltime)
-	infoLog.Printf(format, v)
+	infoLog.Printf(format, v...)
 }
 
 // LogError лог с ошибкой
@@ -31,7 +32,7 @@ func LogError(err error) {
 // LogAppException лог AppException
 func LogAppException(appException *exception.AppException) {
 	errorLog := log.New(os.Stderr, "ERROR\t", log.Ldate|log.Ltime|log.Lshortfile)
-	errorLog.Println(appException.Error.Error())
+	errorLog.Println(appException.Error())
 }
 
 // FormattedInfo Форматированный лог
@@ -62,10 +63,41 @@ func FormattedLog(level string, serviceName string, method string, uri string, s
 
 // FormattedLogWithAppInfo Форматированный лог для RequestData
 func FormattedLogWithAppInfo(appInfo *config.AppInfo, message string) {
-	FormattedInfo(appInfo.ServiceName, appInfo.RequestMethod, appInfo.RequestUrl, 0, appInfo.RequestId, message)
+	FormattedInfo(appInfo.ServiceName, appInfo.RequestMethod, appInfo.RequestUrl, 0, appInfo.RequestId, withClient(appInfo, message))
 }
 
 // FormattedErrorWithAppInfo Форматированный лог ошибки для RequestData
 func FormattedErrorWithAppInfo(appInfo *config.AppInfo, message string) {
-	FormattedInfo(appInfo.ServiceName, appInfo.RequestMethod, appInfo.RequestUrl, 1, appInfo.RequestId, message)
+	FormattedInfo(appInfo.ServiceName, appInfo.RequestMethod, appInfo.RequestUrl, 1, appInfo.RequestId, withClient(appInfo, message))
+}
+
+// InfoContext Форматированный лог с данными запроса из контекста (см. config.WithAppInfo), без них - обычный лог
+func InfoContext(ctx context.Context, message string) {
+	if appInfo := config.AppInfoFromContext(ctx); appInfo != nil {
+		FormattedLogWithAppInfo(appInfo, message)
+
+		return
+	}
+
+	Info("%s", message)
+}
+
+// ErrorContext Форматированный лог ошибки с данными запроса из контекста, без них - обычный лог ошибки
+func ErrorContext(ctx context.Context, message string) {
+	if appInfo := config.AppInfoFromContext(ctx); appInfo != nil {
+		FormattedErrorWithAppInfo(appInfo, message)
+
+		return
+	}
+
+	Error("%s", message)
+}
+
+// withClient добавляет к сообщению клиента, вызвавшего сервис по API ключу или HMAC подписи
+func withClient(appInfo *config.AppInfo, message string) string {
+	if appInfo.ClientId == "" {
+		return message
+	}
+
+	return "[client:" + appInfo.ClientId + "] " + message
 }
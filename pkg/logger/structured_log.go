@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/ZhanibekTau/go-sdk/pkg/constants"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	// FormatJson - одна запись - один JSON объект в строке
+	FormatJson string = "json"
+	// FormatLogfmt - одна запись - строка key=value
+	FormatLogfmt string = "logfmt"
+)
+
+// Field - поле структурированной записи лога
+type Field struct {
+	Key   string
+	Value any
+	// Color - ANSI цвет значения, применяется только в logfmt (для вывода в терминал)
+	Color string
+}
+
+// WriteStructured Пишет одну структурированную запись лога в формате json или logfmt, порядок полей сохраняется
+func WriteStructured(w io.Writer, format string, fields []Field) error {
+	var line []byte
+	var err error
+
+	if format == FormatLogfmt {
+		line = formatLogfmt(fields)
+	} else {
+		line, err = formatJson(fields)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(line, '\n'))
+
+	return err
+}
+
+func formatJson(fields []Field) ([]byte, error) {
+	buf := bytes.Buffer{}
+	buf.WriteByte('{')
+
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(field.Key)
+		value, err := json.Marshal(field.Value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+func formatLogfmt(fields []Field) []byte {
+	buf := bytes.Buffer{}
+
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+
+		value := fmt.Sprint(field.Value)
+
+		if value == "" || strings.ContainsAny(value, " =\"\t\n") {
+			value = strconv.Quote(value)
+		}
+
+		if field.Color != "" {
+			value = field.Color + value + constants.Reset
+		}
+
+		buf.WriteString(field.Key + "=" + value)
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+// Метрики создаются один раз при старте после InitMetrics (например, в PrepareComponents), чтобы получить namespace
+// сервиса. Метрика регистрируется в Registry в конструкторе, повторное имя возвращает ошибку при старте:
+//
+//	ordersCreated, err := metrics.NewCounter("orders_created_total", "Created orders", "channel")
+//
+//	ordersCreated.Inc("mobile")
+
+// Counter - счетчик
+type Counter struct {
+	vec *prometheus.CounterVec
+}
+
+// NewCounter - счетчик с метками labels, зарегистрированный в Registry
+func NewCounter(name string, help string, labels ...string) (*Counter, error) {
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Namespace: Namespace(), Name: name, Help: help}, labels)
+
+	if err := Registry.Register(vec); err != nil {
+		return nil, err
+	}
+
+	return &Counter{vec: vec}, nil
+}
+
+// Inc - увеличивает счетчик на 1, значения меток в порядке объявления
+func (m *Counter) Inc(labelValues ...string) {
+	m.vec.WithLabelValues(labelValues...).Inc()
+}
+
+// Add - увеличивает счетчик на value
+func (m *Counter) Add(value float64, labelValues ...string) {
+	m.vec.WithLabelValues(labelValues...).Add(value)
+}
+
+// Histogram - гистограмма
+type Histogram struct {
+	vec *prometheus.HistogramVec
+}
+
+// NewHistogram - гистограмма с метками labels, зарегистрированная в Registry. Бакеты nil - бакеты из METRICS_BUCKETS
+func NewHistogram(name string, help string, buckets []float64, labels ...string) (*Histogram, error) {
+	if buckets == nil {
+		buckets = Buckets()
+	}
+
+	vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{Namespace: Namespace(), Name: name, Help: help, Buckets: buckets}, labels)
+
+	if err := Registry.Register(vec); err != nil {
+		return nil, err
+	}
+
+	return &Histogram{vec: vec}, nil
+}
+
+// Observe - добавляет значение
+func (m *Histogram) Observe(value float64, labelValues ...string) {
+	m.vec.WithLabelValues(labelValues...).Observe(value)
+}
+
+// ObserveDuration - добавляет время с start в секундах, удобно через defer:
+//
+//	defer jobDuration.ObserveDuration(time.Now(), "import")
+func (m *Histogram) ObserveDuration(start time.Time, labelValues ...string) {
+	m.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+// Gauge - значение, которое может расти и уменьшаться
+type Gauge struct {
+	vec *prometheus.GaugeVec
+}
+
+// NewGauge - gauge с метками labels, зарегистрированный в Registry
+func NewGauge(name string, help string, labels ...string) (*Gauge, error) {
+	vec := prometheus.NewGaugeVec(prometheus.GaugeOpts{Namespace: Namespace(), Name: name, Help: help}, labels)
+
+	if err := Registry.Register(vec); err != nil {
+		return nil, err
+	}
+
+	return &Gauge{vec: vec}, nil
+}
+
+// Set - устанавливает значение
+func (m *Gauge) Set(value float64, labelValues ...string) {
+	m.vec.WithLabelValues(labelValues...).Set(value)
+}
+
+// Inc - увеличивает значение на 1
+func (m *Gauge) Inc(labelValues ...string) {
+	m.vec.WithLabelValues(labelValues...).Inc()
+}
+
+// Dec - уменьшает значение на 1
+func (m *Gauge) Dec(labelValues ...string) {
+	m.vec.WithLabelValues(labelValues...).Dec()
+}
+
+// Add - добавляет value, может быть отрицательным
+func (m *Gauge) Add(value float64, labelValues ...string) {
+	m.vec.WithLabelValues(labelValues...).Add(value)
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"github.com/ZhanibekTau/go-sdk/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// unmatchedRoute - метка для запросов без маршрута, чтобы несуществующие пути не раздували число серий
+const unmatchedRoute = "unmatched"
+
+var (
+	httpOnce            sync.Once
+	httpErr             error
+	httpRequests        *Counter
+	httpRequestDuration *Histogram
+	httpResponseSize    *Histogram
+	httpInFlight        *Gauge
+)
+
+// registerHttpMetrics - регистрирует HTTP метрики один раз, для всех роутеров сервиса
+func registerHttpMetrics() error {
+	httpOnce.Do(func() {
+		if httpRequests, httpErr = NewCounter("http_requests_total", "HTTP requests by route template, method and status", "route", "method", "status"); httpErr != nil {
+			return
+		}
+
+		if httpRequestDuration, httpErr = NewHistogram("http_request_duration_seconds", "HTTP request duration by route template and method", nil, "route", "method"); httpErr != nil {
+			return
+		}
+
+		if httpResponseSize, httpErr = NewHistogram("http_response_size_bytes", "HTTP response size by route template", prometheus.ExponentialBuckets(100, 10, 6), "route"); httpErr != nil {
+			return
+		}
+
+		httpInFlight, httpErr = NewGauge("http_requests_in_flight", "HTTP requests in progress")
+	})
+
+	return httpErr
+}
+
+// HttpMiddleware - HTTP метрики запросов. Маршрут в метках - шаблон (/orders/:id), а не путь запроса.
+// Метрики регистрируются при создании middleware, поэтому оно подключается после InitMetrics.
+// Если метрики не зарегистрировались, ошибка пишется в лог при старте и запросы проходят без метрик
+func HttpMiddleware() gin.HandlerFunc {
+	if err := registerHttpMetrics(); err != nil {
+		logger.Error("http metrics register error: %v", err)
+
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	return func(c *gin.Context) {
+		if isExcluded(c.Request.URL.Path) {
+			c.Next()
+
+			return
+		}
+
+		start := time.Now()
+		httpInFlight.Inc()
+		defer httpInFlight.Dec()
+
+		c.Next()
+
+		route := c.FullPath()
+
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		size := c.Writer.Size()
+
+		if size < 0 {
+			size = 0
+		}
+
+		httpRequests.Inc(route, c.Request.Method, strconv.Itoa(c.Writer.Status()))
+		httpRequestDuration.ObserveDuration(start, route, c.Request.Method)
+		httpResponseSize.Observe(float64(size), route)
+	}
+}
+
+// Handler - отдает метрики общего реестра и метрики, зарегистрированные в реестре prometheus по умолчанию (go, process)
+func Handler() gin.HandlerFunc {
+	handler := promhttp.HandlerFor(prometheus.Gatherers{Registry, prometheus.DefaultGatherer}, promhttp.HandlerOpts{})
+
+	return gin.WrapH(handler)
+}
@@ -0,0 +1,130 @@
+// Package metrics - метрики Prometheus сервиса в общем реестре Registry.
+//
+// HTTP метрики заменили метрики go-gin-prometheus с префиксом ginHelpers, дашборды и алерты нужно перевести:
+//   - ginHelpers_requests_total{code,method,handler,host,url} -> <namespace>_http_requests_total{route,method,status}
+//   - ginHelpers_request_duration_seconds (summary) -> <namespace>_http_request_duration_seconds{route,method} (histogram)
+//   - ginHelpers_response_size_bytes (summary) -> <namespace>_http_response_size_bytes{route} (histogram)
+//   - ginHelpers_request_size_bytes - больше не отдается
+//
+// Новая метрика <namespace>_http_requests_in_flight. namespace - имя приложения (APP_NAME),
+// в метке route шаблон маршрута (/orders/:id), а не путь запроса
+package metrics
+
+import (
+	"github.com/ZhanibekTau/go-sdk/pkg/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Config - настройки метрик, читаются из переменных окружения
+type Config struct {
+	// Namespace - префикс всех метрик сервиса, по умолчанию имя приложения
+	Namespace string `mapstructure:"APP_NAME" json:"app_name"`
+	// Path - путь, по которому отдаются метрики, по умолчанию /metrics
+	Path string `mapstructure:"METRICS_PATH" json:"metrics_path"`
+	// Buckets - границы бакетов гистограмм в секундах через запятую, по умолчанию prometheus.DefBuckets
+	Buckets string `mapstructure:"METRICS_BUCKETS" json:"metrics_buckets"`
+	// ExcludePaths - пути через запятую, запросы к которым не попадают в HTTP метрики, например /health
+	ExcludePaths string `mapstructure:"METRICS_EXCLUDE_PATHS" json:"metrics_exclude_paths"`
+}
+
+// Registry - общий реестр метрик сервиса: HTTP метрики, метрики SDK и бизнес метрики
+var Registry = prometheus.NewRegistry()
+
+var (
+	initOnce     sync.Once
+	mu           sync.RWMutex
+	namespace    string
+	path         = "/metrics"
+	buckets      = prometheus.DefBuckets
+	excludePaths = map[string]bool{}
+	invalidChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+)
+
+// InitMetrics - читает настройки метрик из переменных окружения, повторные вызовы ничего не делают.
+// Вызывается при старте приложения до первого использования метрик
+func InitMetrics() error {
+	var err error
+
+	initOnce.Do(func() {
+		cfg := &Config{}
+
+		if err = config.InitConfig(cfg); err != nil {
+			return
+		}
+
+		Init(cfg)
+	})
+
+	return err
+}
+
+// Init - применяет настройки метрик
+func Init(cfg *Config) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	namespace = strings.Trim(invalidChars.ReplaceAllString(cfg.Namespace, "_"), "_")
+
+	if cfg.Path != "" {
+		path = cfg.Path
+	}
+
+	if parsed := parseBuckets(cfg.Buckets); len(parsed) > 0 {
+		buckets = parsed
+	}
+
+	excludePaths = map[string]bool{path: true}
+
+	for _, excludePath := range strings.Split(cfg.ExcludePaths, ",") {
+		if excludePath = strings.TrimSpace(excludePath); excludePath != "" {
+			excludePaths[excludePath] = true
+		}
+	}
+}
+
+// Namespace - префикс метрик сервиса
+func Namespace() string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return namespace
+}
+
+// Path - путь, по которому отдаются метрики
+func Path() string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return path
+}
+
+// Buckets - границы бакетов гистограмм по умолчанию
+func Buckets() []float64 {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return buckets
+}
+
+func isExcluded(requestPath string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return excludePaths[requestPath]
+}
+
+func parseBuckets(value string) []float64 {
+	result := make([]float64, 0)
+
+	for _, part := range strings.Split(value, ",") {
+		if bucket, err := strconv.ParseFloat(strings.TrimSpace(part), 64); err == nil {
+			result = append(result, bucket)
+		}
+	}
+
+	return result
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill-amqp/v2/pkg/amqp"
 	"github.com/ThreeDotsLabs/watermill/message"
+	sdkConfig "github.com/ZhanibekTau/go-sdk/pkg/config"
+	"github.com/ZhanibekTau/go-sdk/pkg/constants"
 	"github.com/ZhanibekTau/go-sdk/pkg/rabbitmq/structures"
 	"github.com/ZhanibekTau/go-sdk/pkg/tracer"
 	"github.com/google/uuid"
@@ -40,12 +42,30 @@ func NewAmqpPubSub(cfg amqp.ConnectionConfig) (*AmqpPubSub, error) {
 
 // Publish - отправка сообщения
 func (a *AmqpPubSub) Publish(payload interface{}, cfg ...Config) error {
+	return a.PublishContext(context.Background(), payload, cfg...)
+}
+
+// PublishContext - отправка сообщения, Request-Id и язык из AppInfo контекста уходят в метаданные сообщения,
+// и консьюмер продолжает тот же request id
+func (a *AmqpPubSub) PublishContext(ctx context.Context, payload interface{}, cfg ...Config) error {
 	msg, err := messageForm(payload)
 
 	if err != nil {
 		return err
 	}
 
+	msg.SetContext(ctx)
+
+	if appInfo := sdkConfig.AppInfoFromContext(ctx); appInfo != nil {
+		if appInfo.RequestId != "" {
+			msg.Metadata.Set(constants.RequestIdHeaderName, appInfo.RequestId)
+		}
+
+		if appInfo.LanguageCode != "" {
+			msg.Metadata.Set(constants.LanguageHeaderName, appInfo.LanguageCode)
+		}
+	}
+
 	cc := &amqp.Config{}
 	for _, opt := range cfg {
 		opt(cc)
@@ -70,6 +90,27 @@ func (a *AmqpPubSub) Publish(payload interface{}, cfg ...Config) error {
 	return nil
 }
 
+// consumerAppInfo - данные обработки сообщения: Request-Id из метаданных сообщения или его UUID,
+// язык из метаданных или русский
+func consumerAppInfo(msg *message.Message, handlerName string) *sdkConfig.AppInfo {
+	appInfo := &sdkConfig.AppInfo{
+		RequestId:     msg.Metadata.Get(constants.RequestIdHeaderName),
+		RequestMethod: "consumer",
+		RequestUrl:    handlerName,
+		LanguageCode:  msg.Metadata.Get(constants.LanguageHeaderName),
+	}
+
+	if appInfo.RequestId == "" {
+		appInfo.RequestId = msg.UUID
+	}
+
+	if appInfo.LanguageCode == "" {
+		appInfo.LanguageCode = constants.LangCodeRu
+	}
+
+	return appInfo
+}
+
 // RegisterHandler - Регистрирование консьюмеров для очередей
 func (a *AmqpPubSub) RegisterHandler(handler structures.Handler, cfg ...Config) error {
 	cc := &amqp.Config{}
@@ -81,7 +122,7 @@ func (a *AmqpPubSub) RegisterHandler(handler structures.Handler, cfg ...Config)
 	consumer, err := amqp.NewSubscriberWithConnection(*cc, watermill.NewStdLogger(true, true), a.conn)
 
 	if err != nil {
-		log.Printf("failed to subscribe to queue", err)
+		log.Printf("failed to subscribe to queue: %v", err)
 
 		return err
 	}
@@ -148,16 +189,13 @@ func (a *AmqpPubSub) Consume(ctx context.Context) error {
 							handlerName = fn.Name()
 						}
 
-						var parentCtx context.Context
+						// данные сообщения для логов и исходящих запросов обработчика (см. config.AppInfoFromContext)
+						parentCtx := sdkConfig.WithAppInfo(ctx, consumerAppInfo(msg, handlerName))
 						var span trace.Span
 
-						if ctx != nil {
-							if tracer.TraceClient != nil && tracer.TraceClient.IsEnabled {
-								parentCtx, span = tracer.TraceClient.CreateSpan(ctx, "[Consumer handle]"+handlerName)
-								defer span.End()
-							}
-						} else {
-							parentCtx = ctx
+						if tracer.TraceClient != nil && tracer.TraceClient.IsEnabled {
+							parentCtx, span = tracer.TraceClient.CreateSpan(parentCtx, "[Consumer handle]"+handlerName)
+							defer span.End()
 						}
 
 						err := config.Handler(parentCtx, msg)
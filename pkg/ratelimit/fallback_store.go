@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/ZhanibekTau/go-sdk/pkg/logger"
+	"sync"
+	"time"
+)
+
+// NewFallbackStore - хранилище, которое при ошибке основного (обычно редиса) переключается на запасное
+// и не обращается к основному в течение cooldown
+func NewFallbackStore(primary IStore, fallback IStore) *FallbackStore {
+	return &FallbackStore{
+		primary:  primary,
+		fallback: fallback,
+		cooldown: 5 * time.Second,
+	}
+}
+
+// FallbackStore - основное хранилище с запасным
+type FallbackStore struct {
+	primary   IStore
+	fallback  IStore
+	cooldown  time.Duration
+	mu        sync.RWMutex
+	downUntil time.Time
+}
+
+// SetCooldown - сколько не обращаться к основному хранилищу после ошибки
+func (s *FallbackStore) SetCooldown(cooldown time.Duration) *FallbackStore {
+	s.cooldown = cooldown
+
+	return s
+}
+
+func (s *FallbackStore) Take(ctx context.Context, key string, rule Rule) (*Result, error) {
+	s.mu.RLock()
+	isDown := time.Now().Before(s.downUntil)
+	s.mu.RUnlock()
+
+	if !isDown {
+		result, err := s.primary.Take(ctx, key, rule)
+
+		if err == nil {
+			return result, nil
+		}
+
+		logger.Error("rate limit store error, switching to fallback: %v", err)
+
+		s.mu.Lock()
+		s.downUntil = time.Now().Add(s.cooldown)
+		s.mu.Unlock()
+	}
+
+	return s.fallback.Take(ctx, key, rule)
+}
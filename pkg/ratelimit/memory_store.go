@@ -0,0 +1,147 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// NewMemoryStore - хранилище лимитов в памяти процесса. Лимиты считаются для каждой реплики отдельно,
+// поэтому используется для локальной разработки и как запасное хранилище, когда редис недоступен
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries:   make(map[string]*memoryEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+// MemoryStore - хранилище лимитов в памяти
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	lastSweep time.Time
+}
+
+type memoryEntry struct {
+	expiresAt time.Time
+	// счетчик для fixed window
+	count int
+	// метки времени запросов для sliding window
+	hits []time.Time
+	// состояние token bucket
+	tokens float64
+	ts     time.Time
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, rule Rule) (*Result, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	key = rule.String() + ":" + key
+	entry, ok := s.entries[key]
+
+	if !ok || (rule.algorithm() == FixedWindow && now.After(entry.expiresAt)) {
+		entry = &memoryEntry{tokens: float64(rule.Limit), ts: now, expiresAt: now.Add(rule.Window)}
+		s.entries[key] = entry
+	}
+
+	switch rule.algorithm() {
+	case FixedWindow:
+		return takeFixedWindow(entry, rule, now), nil
+	case SlidingWindow:
+		return takeSlidingWindow(entry, rule, now), nil
+	case TokenBucket:
+		return takeTokenBucket(entry, rule, now), nil
+	}
+
+	return nil, errors.New("unknown rate limit algorithm: " + rule.Algorithm)
+}
+
+// sweep - раз в минуту удаляет истекшие записи
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+
+	for key, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+
+	s.lastSweep = now
+}
+
+func takeFixedWindow(entry *memoryEntry, rule Rule, now time.Time) *Result {
+	reset := entry.expiresAt.Sub(now)
+	entry.count++
+
+	if entry.count > rule.Limit {
+		return &Result{Limit: rule.Limit, ResetAfter: reset, RetryAfter: reset}
+	}
+
+	return &Result{Allowed: true, Limit: rule.Limit, Remaining: rule.Limit - entry.count, ResetAfter: reset}
+}
+
+func takeSlidingWindow(entry *memoryEntry, rule Rule, now time.Time) *Result {
+	from := now.Add(-rule.Window)
+	hits := entry.hits[:0]
+
+	for _, hit := range entry.hits {
+		if hit.After(from) {
+			hits = append(hits, hit)
+		}
+	}
+
+	entry.hits = hits
+	allowed := len(entry.hits) < rule.Limit
+
+	if allowed {
+		entry.hits = append(entry.hits, now)
+		entry.expiresAt = now.Add(rule.Window)
+	}
+
+	reset := rule.Window
+
+	if len(entry.hits) > 0 {
+		reset = entry.hits[0].Add(rule.Window).Sub(now)
+	}
+
+	if !allowed {
+		return &Result{Limit: rule.Limit, ResetAfter: reset, RetryAfter: reset}
+	}
+
+	return &Result{Allowed: true, Limit: rule.Limit, Remaining: rule.Limit - len(entry.hits), ResetAfter: reset}
+}
+
+func takeTokenBucket(entry *memoryEntry, rule Rule, now time.Time) *Result {
+	// токенов в миллисекунду
+	rate := float64(rule.Limit) / float64(rule.Window.Milliseconds())
+	elapsed := float64(now.Sub(entry.ts).Milliseconds())
+	entry.tokens = math.Min(float64(rule.Limit), entry.tokens+math.Max(0, elapsed)*rate)
+	entry.ts = now
+	entry.expiresAt = now.Add(rule.Window)
+
+	result := &Result{Limit: rule.Limit}
+
+	if entry.tokens >= 1 {
+		entry.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration(math.Ceil((1-entry.tokens)/rate)) * time.Millisecond
+	}
+
+	result.Remaining = int(math.Floor(entry.tokens))
+	result.ResetAfter = time.Duration(math.Ceil((float64(rule.Limit)-entry.tokens)/rate)) * time.Millisecond
+
+	return result
+}
@@ -0,0 +1,130 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+// Скрипты возвращают {allowed, remaining, retry_after_ms, reset_after_ms}
+var fixedWindowScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+local ttl = redis.call('PTTL', KEYS[1])
+local limit = tonumber(ARGV[2])
+if count > limit then
+	return {0, 0, ttl, ttl}
+end
+return {1, limit - count, 0, ttl}
+`)
+
+var slidingWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], 0, now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	redis.call('PEXPIRE', KEYS[1], window)
+	count = count + 1
+	allowed = 1
+end
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+local reset = window
+if oldest[2] then
+	reset = window - (now - tonumber(oldest[2]))
+end
+if allowed == 1 then
+	return {1, limit - count, 0, reset}
+end
+return {0, 0, reset, reset}
+`)
+
+var tokenBucketScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+local rate = limit / window
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(data[1]) or limit
+local ts = tonumber(data[2]) or now
+tokens = math.min(limit, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], window)
+return {allowed, math.floor(tokens), retry, math.ceil((limit - tokens) / rate)}
+`)
+
+// NewRedisStore - хранилище лимитов в редисе, общее для всех реплик сервиса
+func NewRedisStore(redisClient *redis.Client) *RedisStore {
+	return &RedisStore{
+		redisClient: redisClient,
+		prefix:      "rate_limit:",
+	}
+}
+
+// RedisStore - хранилище лимитов в редисе
+type RedisStore struct {
+	redisClient *redis.Client
+	prefix      string
+}
+
+// SetPrefix - префикс ключей в редисе
+func (s *RedisStore) SetPrefix(prefix string) *RedisStore {
+	s.prefix = prefix
+
+	return s
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, rule Rule) (*Result, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	var values []int64
+	var err error
+
+	key = s.prefix + rule.String() + ":" + key
+	window := rule.Window.Milliseconds()
+	now := time.Now().UnixMilli()
+
+	switch rule.algorithm() {
+	case FixedWindow:
+		values, err = fixedWindowScript.Run(ctx, s.redisClient, []string{key}, window, rule.Limit).Int64Slice()
+	case SlidingWindow:
+		values, err = slidingWindowScript.Run(ctx, s.redisClient, []string{key}, now, window, rule.Limit, uuid.New().String()).Int64Slice()
+	case TokenBucket:
+		values, err = tokenBucketScript.Run(ctx, s.redisClient, []string{key}, now, window, rule.Limit).Int64Slice()
+	default:
+		return nil, errors.New("unknown rate limit algorithm: " + rule.Algorithm)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(values) != 4 {
+		return nil, errors.New("unexpected rate limit script result")
+	}
+
+	return &Result{
+		Allowed:    values[0] == 1,
+		Limit:      rule.Limit,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		ResetAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
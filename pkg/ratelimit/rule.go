@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+const (
+	// FixedWindow - счетчик запросов в фиксированном окне (поведение старого RateLimiterMiddleware)
+	FixedWindow string = "fixed_window"
+	// SlidingWindow - лог запросов в скользящем окне, без всплесков на границе окон
+	SlidingWindow string = "sliding_window"
+	// TokenBucket - корзина токенов, допускает всплески до Limit и равномерно пополняется за Window
+	TokenBucket string = "token_bucket"
+)
+
+// Rule - правило ограничения: не больше Limit запросов за Window
+type Rule struct {
+	Limit     int
+	Window    time.Duration
+	Algorithm string
+}
+
+// PerSecond - правило на секунду
+func PerSecond(limit int, algorithm string) Rule {
+	return Rule{Limit: limit, Window: time.Second, Algorithm: algorithm}
+}
+
+// PerMinute - правило на минуту
+func PerMinute(limit int, algorithm string) Rule {
+	return Rule{Limit: limit, Window: time.Minute, Algorithm: algorithm}
+}
+
+// PerHour - правило на час
+func PerHour(limit int, algorithm string) Rule {
+	return Rule{Limit: limit, Window: time.Hour, Algorithm: algorithm}
+}
+
+// PerDay - правило на сутки
+func PerDay(limit int, algorithm string) Rule {
+	return Rule{Limit: limit, Window: 24 * time.Hour, Algorithm: algorithm}
+}
+
+// String - представление правила, используется в ключе хранилища, чтобы разные правила не делили счетчик
+func (r Rule) String() string {
+	return r.algorithm() + ":" + strconv.Itoa(r.Limit) + ":" + strconv.FormatInt(r.Window.Milliseconds(), 10)
+}
+
+// Validate - проверка правила: лимит больше нуля, окно не меньше миллисекунды (хранилища считают в миллисекундах),
+// известный алгоритм
+func (r Rule) Validate() error {
+	if r.Limit <= 0 {
+		return errors.New("rate limit rule: limit must be positive")
+	}
+
+	if r.Window < time.Millisecond {
+		return errors.New("rate limit rule: window must be at least 1ms")
+	}
+
+	switch r.algorithm() {
+	case FixedWindow, SlidingWindow, TokenBucket:
+		return nil
+	}
+
+	return errors.New("unknown rate limit algorithm: " + r.Algorithm)
+}
+
+func (r Rule) algorithm() string {
+	if r.Algorithm == "" {
+		return SlidingWindow
+	}
+
+	return r.Algorithm
+}
+
+// Result - результат проверки лимита
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter - через сколько лимит полностью восстановится
+	ResetAfter time.Duration
+	// RetryAfter - через сколько можно повторить запрос, если он отклонен
+	RetryAfter time.Duration
+}
@@ -0,0 +1,9 @@
+package ratelimit
+
+import "context"
+
+// IStore - хранилище счетчиков лимитов
+type IStore interface {
+	// Take - учитывает запрос по ключу и возвращает, разрешен ли он
+	Take(ctx context.Context, key string, rule Rule) (*Result, error)
+}
@@ -0,0 +1,175 @@
+package redact
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Mask - значение, которым заменяются скрытые поля
+const Mask string = "***"
+
+// jsonKeyRegexp - ключ объекта JSON с двоеточием
+var jsonKeyRegexp = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"\s*:\s*`)
+
+// DefaultDenyList - поля, которые скрываются по умолчанию
+var DefaultDenyList = []string{"password", "token", "secret", "authorization", "iin", "card", "cvv"}
+
+// IsDenied - скрывается ли поле. Сравнение без учета регистра и по вхождению, так что "token" скроет и "access_token"
+func IsDenied(key string, denyList []string) bool {
+	key = strings.ToLower(key)
+
+	for _, denied := range denyList {
+		if strings.Contains(key, strings.ToLower(denied)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MaskJson - скрывает значения полей из denyList на любом уровне вложенности JSON.
+// Тело, которое не является JSON, возвращается без изменений
+func MaskJson(body []byte, denyList []string) []byte {
+	var data any
+
+	if err := json.Unmarshal(body, &data); err != nil {
+		return body
+	}
+
+	masked, err := json.Marshal(maskValue(data, denyList))
+
+	if err != nil {
+		return body
+	}
+
+	return masked
+}
+
+// MaskJsonPrefix - скрывает значения полей из denyList в начале JSON, обрезанного до лимита, который нельзя разобрать
+// целиком. Строки и числа скрываются до конца значения, вложенный объект или массив скрываемого поля - вместе
+// с остатком тела
+func MaskJsonPrefix(body []byte, denyList []string) []byte {
+	result := make([]byte, 0, len(body))
+	last := 0
+
+	for _, match := range jsonKeyRegexp.FindAllSubmatchIndex(body, -1) {
+		if match[0] < last || !IsDenied(string(body[match[2]:match[3]]), denyList) {
+			continue
+		}
+
+		result = append(result, body[last:match[1]]...)
+		result = append(result, '"')
+		result = append(result, Mask...)
+		result = append(result, '"')
+		last = valueEnd(body, match[1])
+	}
+
+	return append(result, body[last:]...)
+}
+
+// valueEnd - позиция после значения JSON, которое начинается с start, для объекта и массива - конец тела
+func valueEnd(body []byte, start int) int {
+	if start >= len(body) {
+		return start
+	}
+
+	switch body[start] {
+	case '{', '[':
+		return len(body)
+	case '"':
+		for i := start + 1; i < len(body); i++ {
+			switch body[i] {
+			case '\\':
+				i++
+			case '"':
+				return i + 1
+			}
+		}
+
+		return len(body)
+	}
+
+	for i := start; i < len(body); i++ {
+		switch body[i] {
+		case ',', '}', ']', ' ', '\n', '\r', '\t':
+			return i
+		}
+	}
+
+	return len(body)
+}
+
+// MaskForm - скрывает значения полей из denyList в application/x-www-form-urlencoded теле
+func MaskForm(body []byte, denyList []string) []byte {
+	values, err := url.ParseQuery(string(body))
+
+	if err != nil {
+		return body
+	}
+
+	for key := range values {
+		if IsDenied(key, denyList) {
+			values[key] = []string{Mask}
+		}
+	}
+
+	return []byte(values.Encode())
+}
+
+// CanMask - можно ли скрыть поля в теле с таким Content-Type: JSON (или тип не указан) и form-urlencoded
+func CanMask(contentType string) bool {
+	mediaType, _, _ := strings.Cut(strings.ToLower(contentType), ";")
+	mediaType = strings.TrimSpace(mediaType)
+
+	return mediaType == "" || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") ||
+		mediaType == "application/x-www-form-urlencoded"
+}
+
+// Omitted - замена тела, поля в котором скрыть нельзя (XML, msgpack и т.п.)
+func Omitted(contentType string) []byte {
+	return []byte("(" + contentType + " body omitted)")
+}
+
+// MaskBody - скрывает поля в теле по его Content-Type. Тело, в котором поля скрыть нельзя (см. CanMask),
+// заменяется на Omitted
+func MaskBody(body []byte, contentType string, denyList []string) []byte {
+	if len(body) > 0 && !CanMask(contentType) {
+		return Omitted(contentType)
+	}
+
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		return MaskForm(body, denyList)
+	}
+
+	return MaskJson(body, denyList)
+}
+
+// Truncate - обрезает тело до limit байт, 0 - без ограничения
+func Truncate(body []byte, limit int) []byte {
+	if limit <= 0 || len(body) <= limit {
+		return body
+	}
+
+	return append(append([]byte{}, body[:limit]...), []byte("...(truncated)")...)
+}
+
+func maskValue(value any, denyList []string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if IsDenied(key, denyList) {
+				v[key] = Mask
+			} else {
+				v[key] = maskValue(item, denyList)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = maskValue(item, denyList)
+		}
+	}
+
+	return value
+}
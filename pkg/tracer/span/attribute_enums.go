@@ -1,6 +1,18 @@
 package span
 
-const AttributeReqBody = "request.body"
+const (
+	AttributeReqBody  = "request.body"
+	AttributeRespBody = "response.body"
+)
+
+const AttributeClientId = "client.id"
+
+const AttributeRequestId = "request.id"
+
+const (
+	AttributeHttpMethod = "http.method"
+	AttributeHttpUrl    = "http.url"
+)
 
 const (
 	AttributeRespHttpCode = "http.status_code"
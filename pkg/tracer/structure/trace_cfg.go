@@ -9,4 +9,6 @@ type TraceConfig struct {
 	ServiceName string `mapstructure:"TRACE_SERVICE_NAME"`
 	// IsHttpBodyEnabled -  этот параметр нужен для того чтобы мидлвар записывал в трэйс все входящие тела запроса -  HTTP BODY
 	IsHttpBodyEnabled bool `mapstructure:"TRACE_IS_HTTP_BODY_ENABLED"`
+	// HttpBodyMaxSize -  сколько байт тела запроса записывается в трэйс, по умолчанию 4096
+	HttpBodyMaxSize int `mapstructure:"TRACE_HTTP_BODY_MAX_SIZE"`
 }
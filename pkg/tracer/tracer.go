@@ -5,7 +5,8 @@ import (
 	"context"
 	"crypto/tls"
 	"github.com/ZhanibekTau/go-sdk/pkg/config"
-	"github.com/ZhanibekTau/go-sdk/pkg/exception"
+	"github.com/ZhanibekTau/go-sdk/pkg/http/helpers"
+	"github.com/ZhanibekTau/go-sdk/pkg/redact"
 	span2 "github.com/ZhanibekTau/go-sdk/pkg/tracer/span"
 	"github.com/ZhanibekTau/go-sdk/pkg/tracer/structure"
 	"github.com/gin-gonic/gin"
@@ -117,6 +118,14 @@ func (t *Tracer) MiddleWareExtractTraceId() gin.HandlerFunc {
 				bodyBytes, _ := io.ReadAll(c.Request.Body)
 				c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 
+				// поля с паролями, токенами и т.п. скрываются, тело обрезается
+				maxSize := t.cfg.HttpBodyMaxSize
+
+				if maxSize == 0 {
+					maxSize = 4096
+				}
+
+				bodyBytes = redact.Truncate(redact.MaskBody(bodyBytes, c.GetHeader(httpheader.ContentType), redact.DefaultDenyList), maxSize)
 				span.SetAttributes(attribute.String(span2.AttributeReqBody, string(bodyBytes)))
 			}
 		}
@@ -125,18 +134,12 @@ func (t *Tracer) MiddleWareExtractTraceId() gin.HandlerFunc {
 		c.Next()
 
 		// парсинг ошибок
-		{
-			excep := c.Keys["exception"]
-
-			switch v := excep.(type) {
-			case *exception.AppException:
-				span.SetAttributes(attribute.Int(span2.AttributeRespHttpCode, v.Code))
-				if v.Error != nil {
-					span.SetAttributes(attribute.String(span2.AttributeRespErrMsg, v.Error.Error()))
-				}
-			default:
-				span.SetAttributes(attribute.Int(span2.AttributeRespHttpCode, c.Writer.Status()))
-			}
+		if appException, exists := helpers.GetAppException(c); exists {
+			span.SetAttributes(attribute.Int(span2.AttributeRespHttpCode, appException.Code))
+			span.SetAttributes(attribute.String(span2.AttributeRespErrMsg, appException.Error()))
+			span.RecordError(appException)
+		} else {
+			span.SetAttributes(attribute.Int(span2.AttributeRespHttpCode, c.Writer.Status()))
 		}
 	}
 }
@@ -144,12 +147,17 @@ func (t *Tracer) MiddleWareExtractTraceId() gin.HandlerFunc {
 // CreateSpan - Создает родительский спан,и возвращает контекст, этот контекст нужен для дочернего спана.
 // В случае если в ctx нет контекста родителя то создается контекст родителя
 // Не забыть вызывать span.End()
-func (t *Tracer) CreateSpan(ctx context.Context, name string) (context.Context, trace2.Span) {
+func (t *Tracer) CreateSpan(ctx context.Context, name string, opts ...trace2.SpanStartOption) (context.Context, trace2.Span) {
 	if t == nil || t.tp == nil {
-		return context.Background(), noop.Span{}
+		// контекст сохраняется, в нем могут быть данные запроса (см. config.WithAppInfo)
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		return ctx, noop.Span{}
 	}
 
-	return t.tp.Tracer(t.ServiceName).Start(ctx, name)
+	return t.tp.Tracer(t.ServiceName).Start(ctx, name, opts...)
 }
 
 // CreateSpanWithCustomTraceId -  экспериментальный метод, создаем спан на основе кастомного трайс айди